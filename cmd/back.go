@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/kernelle-soft/gimme/internal/history"
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/spf13/cobra"
+)
+
+var backCommand = &cobra.Command{
+	Use:   "back [n]",
+	Short: "Jump back to a previous location",
+	Long: `Jump back to a location you were in before. Without an argument, goes back one step, same as 'gimme -'.
+
+Going back counts as a move too, so 'gimme -' twice returns to where you started. The numbers match the ones 'gimme history' lists. Each shell keeps its own history when gimme's shell integration is loaded; otherwise the history is shared.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n := 1
+		if len(args) == 1 {
+			var err error
+			if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+				log.Print("\"{}\" is not a number of steps.", args[0])
+				return
+			}
+		}
+		goBack(n)
+	},
+}
+
+// goBack jumps to the directory n steps back in the history.
+func goBack(n int) {
+	store, err := history.Load()
+	if err != nil {
+		log.Error("Could not load directory history: {}", err)
+		return
+	}
+
+	cwd, _ := os.Getwd()
+	target, ok := store.Back(history.Session(), cwd, n)
+	if !ok {
+		if n == 1 {
+			log.Print("No previous location to go back to.")
+		} else {
+			log.Print("History doesn't go back {} locations.", n)
+		}
+		return
+	}
+
+	land(target)
+	recordVisit(target)
+}
@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"os"
+	"slices"
 
-	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/hook"
 	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/repo"
 	"github.com/kernelle-soft/gimme/internal/search"
 	"github.com/kernelle-soft/gimme/internal/slice"
 	"github.com/spf13/cobra"
@@ -16,12 +18,13 @@ var (
 	cleanDryRunFlag  bool
 	cleanForceFlag   bool
 	cleanVerboseFlag bool
+	cleanSelectFlag  string
 )
 
 var cleanCommand = &cobra.Command{
-	Use:   "clean",
+	Use:   "clean [branch...]",
 	Short: "Clean up branches",
-	Long: `Clean up branches in the current repository.
+	Long: `Clean up branches in the current repository, or with --select in every repository matching an expression such as 'tag:backend'.
 
 Requires -b flag for branch cleaning (future: may support other clean operations).
 
@@ -29,13 +32,17 @@ With -b flag: bulk delete branches with protection awareness.
   gimme clean -b            - delete merged branches (default)
   gimme clean -b --all      - delete all non-pinned branches
   gimme clean -b --dry-run  - preview without deleting
+  gimme clean -b feat/x     - only consider the branches named; the rules below still apply
 
 Protection hierarchy (branches that won't be deleted):
   1. Current branch — always protected
-  2. Global pins (main, master, etc.) — always protected (even with --force)
+  2. Protected branches (main, master, etc., and those in the repository's .gimme.yaml) — always protected (even with --force)
   3. Per-repo pinned branches — protected unless --force
-  4. Branches with active worktrees — always skipped`,
-	Run: cleanRun,
+  4. Branches with active worktrees — always skipped
+
+Each branch's pre-clean hooks run before it's deleted, and a hook that fails keeps the branch. Dry runs don't run hooks. See 'hooks' in the config.`,
+	Run:               cleanRun,
+	ValidArgsFunction: completeCleanArgs,
 }
 
 func init() {
@@ -44,19 +51,27 @@ func init() {
 	cleanCommand.Flags().BoolVar(&cleanDryRunFlag, "dry-run", false, "Preview without deleting")
 	cleanCommand.Flags().BoolVar(&cleanForceFlag, "force", false, "Include per-repo pinned branches")
 	cleanCommand.Flags().BoolVarP(&cleanVerboseFlag, "verbose", "v", false, "Show each deleted branch")
+	cleanCommand.Flags().StringVarP(&cleanSelectFlag, "select", "s", "", selectUsage)
 }
 
 var cleanRun = func(cmd *cobra.Command, args []string) {
 	if !cleanBranchFlag {
 		log.Print("Please specify -b flag to clean branches.")
-		log.Print("Usage: gimme clean -b [--all] [--dry-run] [--force] [-v]")
+		log.Print("Usage: gimme clean -b [--all] [--dry-run] [--force] [-v] [branch...]")
 		return
 	}
 
-	cleanBranches()
+	cleanBranches(args)
 }
 
-func cleanBranches() {
+// cleanBranches cleans the current repository, or those --select picks. With
+// names, only those branches are considered.
+func cleanBranches(names []string) {
+	if cleanSelectFlag != "" {
+		cleanSelected(names)
+		return
+	}
+
 	// Get current working directory to determine which repo we're in
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -71,27 +86,69 @@ func cleanBranches() {
 		return
 	}
 
+	branches := currentRepo.ListBranches()
+	for _, name := range names {
+		if !slice.Contains(branches, name) {
+			log.Print("Branch \"{}\" not found.", name)
+		}
+	}
+	cleanRepo(currentRepo, names)
+}
+
+// cleanSelected cleans the branches of every repository --select picks, one
+// after another. Linked worktrees are left to their repository.
+func cleanSelected(names []string) {
+	selector, ok := parseSelect(cleanSelectFlag)
+	if !ok {
+		return
+	}
+
+	repos := search.Repositories(search.DefaultRepoSearchOptions())
+	repos = slices.DeleteFunc(repos, func(r repo.Repo) bool { return r.IsWorktree() })
+	repos = selector.Filter(repos, jobs(0))
+	search.SortByPins(repos)
+	if len(repos) == 0 {
+		log.Print("No repositories selected.")
+		return
+	}
+
+	for i := range repos {
+		if i > 0 {
+			log.Print("")
+		}
+		log.Print("{}:", repos[i].Name)
+		cleanRepo(&repos[i], names)
+	}
+}
+
+// cleanRepo deletes the branches of r that the flags and protections allow,
+// only looking at those in names if any are given.
+func cleanRepo(r *repo.Repo, names []string) {
 	// Get protection lists
-	globalPins := config.GetGlobalPinnedBranches()
-	repoPins := config.GetRepoPinnedBranches()
-	repoPinnedBranches := repoPins[currentRepo.Identifier]
+	settings := search.SettingsFor(r.Identifier, r.Path)
+	protected := settings.Protected
+	repoPinnedBranches := settings.Pinned
 
 	// Get all branches and current branch
-	branches := currentRepo.ListBranches()
-	currentBranch := currentRepo.CurrentBranch()
+	branches := r.ListBranches()
+	currentBranch := r.CurrentBranch()
 
 	// Determine which branches to delete
 	var toDelete []string
 	var skipped []string
 
 	for _, branch := range branches {
+		if len(names) > 0 && !slice.Contains(names, branch) {
+			continue
+		}
+
 		// Protection check 1: Current branch — always protected
 		if branch == currentBranch {
 			continue
 		}
 
-		// Protection check 2: Global pins — always protected (even with --force)
-		if slice.Contains(globalPins, branch) {
+		// Protection check 2: Protected branches — always protected (even with --force)
+		if slice.Contains(protected, branch) {
 			continue
 		}
 
@@ -101,15 +158,15 @@ func cleanBranches() {
 		}
 
 		// Protection check 4: Branches with active worktrees — always skipped
-		if currentRepo.HasWorktree(branch) {
+		if r.HasWorktree(branch) {
 			skipped = append(skipped, branch)
 			continue
 		}
 
 		// Apply filter: default is merged-only, --all skips this check
 		if !cleanAllFlag {
-			// Only delete if merged into any global pin
-			if !currentRepo.IsMerged(branch, globalPins) {
+			// Only delete if merged into any protected branch
+			if !r.IsMerged(branch, protected) {
 				continue
 			}
 		}
@@ -137,10 +194,14 @@ func cleanBranches() {
 		return
 	}
 
-	// Delete branches
+	// Delete branches, unless a pre-clean hook vetoes it
 	deletedCount := 0
 	for _, branch := range toDelete {
-		err := currentRepo.DeleteBranch(branch)
+		if err := hook.Run(hook.PreClean, hook.Target{Path: r.Path, Identifier: r.Identifier, Branch: branch}); err != nil {
+			log.Print("Kept branch \"{}\": {}.", branch, err)
+			continue
+		}
+		err := r.DeleteBranch(branch)
 		if err != nil {
 			log.Warning("Failed to delete branch \"{}\": {}", branch, err)
 			continue
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+
+	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/hook"
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/path"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var (
+	clonePathFlag string
+	clonePinFlag  bool
+)
+
+var cloneCommand = &cobra.Command{
+	Use:   "clone <url>",
+	Short: "Clone a repository into the workspace",
+	Long: `Clone a repository and jump into it.
+
+Clones go under clone.root, which defaults to the first search group, at the path clone.layout gives. The default layout is "{host}/{owner}/{repo}", so git@github.com:acme/api.git is cloned to <root>/github.com/acme/api. Layouts may also use {identifier}, the whole normalized URL.
+
+If a repository with the same identifier is already in a search group, gimme jumps to it instead of cloning it again.
+
+New clones run their post-clone hooks, if any are configured, before gimme jumps into them. See 'hooks' in the config.
+
+Example:
+	gimme clone git@github.com:acme/api.git
+	gimme clone https://gitlab.com/acme/platform/api --pin
+	gimme clone ../api.git --path ~/scratch/api   # URLs without a host and owner need --path`,
+	Args:              cobra.ExactArgs(1),
+	Run:               cloneRun,
+	ValidArgsFunction: cobra.NoFileCompletions,
+}
+
+func init() {
+	cloneCommand.Flags().StringVar(&clonePathFlag, "path", "", "Clone to this path instead of the configured layout")
+	cloneCommand.Flags().BoolVar(&clonePinFlag, "pin", false, "Pin the repository once it's cloned")
+}
+
+var cloneRun = func(cmd *cobra.Command, args []string) {
+	url := args[0]
+	identifier := repo.NormalizeRemoteURL(url)
+	if identifier != "" {
+		if existing := search.FindByIdentifier(identifier); existing != nil {
+			log.Print("{} is already cloned at \"{}\".", identifier, existing.Path)
+			jumpInto(existing.Path, "", search.SettingsFor(existing.Identifier, existing.Path).Entry)
+			return
+		}
+	}
+
+	dest, err := cloneDestination(identifier)
+	if err != nil {
+		log.Error("Could not work out where to clone \"{}\": {}", url, err)
+		return
+	}
+	if existing := search.FindRepoForPath(dest); existing != nil && existing.Path == dest {
+		if identifier == "" || existing.Identifier != identifier {
+			log.Error("\"{}\" already holds another repository ({}).", dest, existing.Identifier)
+			return
+		}
+		log.Print("{} is already cloned at \"{}\".", identifier, dest)
+		jumpInto(dest, "", search.SettingsFor(identifier, dest).Entry)
+		return
+	}
+
+	log.Print("Cloning {} into \"{}\"...", url, dest)
+	cloned, err := repo.Clone(url, dest)
+	if err != nil {
+		log.Error("Failed to clone \"{}\": {}", url, err)
+		return
+	}
+	runPostClone(cloned)
+
+	if clonePinFlag {
+		config.AddPinnedRepo(dest)
+	}
+	if !slices.ContainsFunc(config.GetSearchFolders(), func(folder string) bool { return path.IsWithin(dest, folder) }) {
+		log.Warning("\"{}\" is outside every search group, so searches won't find it. See 'gimme config add group'.", dest)
+	}
+
+	jumpInto(dest, "", search.SettingsFor(cloned.Identifier, dest).Entry)
+}
+
+// runPostClone runs the post-clone hooks of a repository just cloned. A
+// failing hook is only warned about; the clone stands.
+func runPostClone(cloned repo.Repo) {
+	target := hook.Target{Path: cloned.Path, Identifier: cloned.Identifier, Branch: cloned.CurrentBranch()}
+	if err := hook.Run(hook.PostClone, target); err != nil {
+		log.Warning("{}.", err)
+	}
+}
+
+// cloneDestination returns where to clone the repository with identifier:
+// --path if given, otherwise its place in the clone layout.
+func cloneDestination(identifier string) (string, error) {
+	if clonePathFlag != "" {
+		normalized, err := path.Normalize(clonePathFlag)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Abs(normalized)
+	}
+	if identifier == "" {
+		return "", errors.New("the URL has no host and owner to lay it out by; pass --path")
+	}
+
+	root, err := config.GetCloneRoot()
+	if err != nil {
+		return "", err
+	}
+	return repo.ClonePath(identifier, root, config.GetCloneLayout())
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/search"
+	"github.com/kernelle-soft/gimme/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+// Shell completion. Every function returns candidates with a short description
+// after a tab, which shells that support it show next to the candidate.
+
+// completeRepos completes the repository argument of a jump: repository names,
+// aliases, identifiers once the query contains a "/", and branches after
+// "repo@".
+func completeRepos(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	if name, branch, ok := strings.Cut(toComplete, "@"); ok {
+		return completeRepoBranches(name, branch), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := []string{}
+	for short, expanded := range config.GetAliases() {
+		completions = append(completions, short+"\talias for "+expanded)
+	}
+
+	seen := map[string]bool{}
+	for _, e := range search.Candidates() {
+		if !seen[e.Name] {
+			seen[e.Name] = true
+			completions = append(completions, e.Name+"\t"+e.Path)
+		}
+		if strings.Contains(toComplete, "/") && !seen[e.Identifier] {
+			seen[e.Identifier] = true
+			completions = append(completions, e.Identifier+"\t"+e.Path)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRepoBranches completes "repo@branch" with the branches of the best
+// match for repo, or of the current repository for "@branch".
+func completeRepoBranches(name, branch string) []string {
+	var branches []string
+	if name == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil
+		}
+		currentRepo := search.FindRepoForPath(cwd)
+		if currentRepo == nil {
+			return nil
+		}
+		branches = currentRepo.ListBranches()
+	} else {
+		// Resolved from the index alone, so completion never walks the disk,
+		// but ranked like the jump so it's the same repository
+		query := name
+		if expanded, ok := config.GetAliases()[name]; ok {
+			query = expanded
+		}
+		found, ok := search.BestCandidate(query)
+		if !ok {
+			return nil
+		}
+		r := repo.Repo{Path: found.Path}
+		if found.Parent != "" {
+			r.Path = found.Parent
+		}
+		branches = r.ListBranches()
+	}
+
+	completions := []string{}
+	for _, b := range branches {
+		if strings.HasPrefix(b, branch) {
+			completions = append(completions, name+"@"+b)
+		}
+	}
+	return completions
+}
+
+// completeBranches completes branch names of the current repository.
+func completeBranches(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	currentRepo := search.FindRepoForPath(cwd)
+	if currentRepo == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	current := currentRepo.CurrentBranch()
+	completions := []string{}
+	for _, b := range currentRepo.ListBranches() {
+		switch {
+		case b == current:
+			completions = append(completions, b+"\tcurrent branch")
+		case config.IsBranchGloballyPinned(b):
+			completions = append(completions, b+"\tprotected")
+		case config.IsBranchPinnedForRepo(currentRepo.Identifier, b):
+			completions = append(completions, b+"\tpinned")
+		default:
+			completions = append(completions, b)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCleanArgs completes the branches to clean with -b, leaving out those
+// already given.
+func completeCleanArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if !cleanBranchFlag || cleanSelectFlag != "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions, shellDirective := completeBranches(cmd, nil, toComplete)
+	completions = slices.DeleteFunc(completions, func(c string) bool {
+		branch, _, _ := strings.Cut(c, "\t")
+		return slices.Contains(args, branch)
+	})
+	return completions, shellDirective
+}
+
+// completePinArg completes pin's argument: a branch with -b, otherwise a
+// directory.
+func completePinArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if pinBranchFlag {
+		return completeBranches(cmd, args, toComplete)
+	}
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveFilterDirs
+}
+
+// completeUnpinArg completes unpin's argument: a pinned branch of the current
+// repository with -b, otherwise a pinned repository.
+func completeUnpinArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	if !unpinBranchFlag {
+		return config.GetPinnedRepos(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	currentRepo := search.FindRepoForPath(cwd)
+	if currentRepo == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return config.GetRepoPinnedBranches()[currentRepo.Identifier], cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSnapshots completes the names of saved snapshots, one for most
+// snapshot commands and two for diff.
+func completeSnapshots(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	maxArgs := 1
+	if cmd.Name() == "diff" {
+		maxArgs = 2
+	}
+	if len(args) >= maxArgs {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, err := snapshot.List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTasks completes the names of the current repository's tasks.
+func completeTasks(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	loc, err := repo.Locate(cwd)
+	currentRepo := search.FindRepoForPath(cwd)
+	if err != nil || currentRepo == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := []string{}
+	for name, task := range search.SettingsFor(currentRepo.Identifier, loc.WorktreeRoot).Tasks {
+		completions = append(completions, name+"\t"+task)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -1,15 +1,21 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/kernelle-soft/gimme/internal/config"
 	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/search"
 	"github.com/spf13/cobra"
 )
 
 var addCommand = &cobra.Command{
 	Use:   "add",
 	Short: "Add configuration values",
-	Long:  `Add configuration values such as search groups or aliases.`,
+	Long:  `Add configuration values such as search groups, aliases, entry directories, on-enter commands or tags.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
@@ -49,8 +55,99 @@ var addProtectedCommand = &cobra.Command{
 	},
 }
 
+var addEntryCommand = &cobra.Command{
+	Use:   "entry <subdir>",
+	Short: "Set the current repository's entry directory",
+	Long:  `Set the subdirectory that jumping to the current repository lands in, e.g. the package you work on in a monorepo. The subdirectory may be given relative to the current directory or to the repository root.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		root, identifier, ok := currentRepoRoot()
+		if !ok {
+			return
+		}
+
+		subdir := args[0]
+		if abs, err := filepath.Abs(subdir); err == nil {
+			if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+				if rel, err := filepath.Rel(root, resolved); err == nil && !strings.HasPrefix(rel, "..") {
+					subdir = rel
+				}
+			}
+		}
+
+		if info, err := os.Stat(filepath.Join(root, subdir)); err != nil || !info.IsDir() {
+			log.Print("\"{}\" is not a directory in this repository.", subdir)
+			return
+		}
+
+		if err := config.SetRepoEntry(identifier, subdir); err != nil {
+			log.Error("Failed to set entry: {}", err)
+		}
+	},
+}
+
+var addOnEnterCommand = &cobra.Command{
+	Use:   "on-enter <command>",
+	Short: "Set a command to run after jumping to the current repository",
+	Long:  `Set a shell command that runs in your shell after jumping to the current repository, e.g. "source .venv/bin/activate" to activate its environment. It needs the shell integration from 'gimme init'.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		_, identifier, ok := currentRepoRoot()
+		if !ok {
+			return
+		}
+		if err := config.SetRepoOnEnter(identifier, strings.Join(args, " ")); err != nil {
+			log.Error("Failed to set on-enter command: {}", err)
+		}
+	},
+}
+
+var addTagCommand = &cobra.Command{
+	Use:   "tag <tag>...",
+	Short: "Tag the current repository",
+	Long:  `Tag the current repository, e.g. "backend" or "infra", so commands can select it with --select tag:<tag>.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		_, identifier, ok := currentRepoRoot()
+		if !ok {
+			return
+		}
+		for _, tag := range args {
+			if err := config.AddRepoTag(identifier, tag); err != nil {
+				log.Error("Failed to add tag: {}", err)
+			}
+		}
+	},
+}
+
 func init() {
 	addCommand.AddCommand(addGroupCommand)
 	addCommand.AddCommand(addAliasCommand)
 	addCommand.AddCommand(addProtectedCommand)
+	addCommand.AddCommand(addEntryCommand)
+	addCommand.AddCommand(addOnEnterCommand)
+	addCommand.AddCommand(addTagCommand)
+}
+
+// currentRepoRoot returns the root of the working tree containing the current
+// directory and its repository's identifier.
+func currentRepoRoot() (string, string, bool) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		log.Error("Could not determine current directory: {}", err)
+		return "", "", false
+	}
+
+	loc, err := repo.Locate(cwd)
+	if err != nil {
+		log.Print("Not in a git repository.")
+		return "", "", false
+	}
+
+	currentRepo := search.FindRepoForPath(cwd)
+	if currentRepo == nil {
+		log.Print("Not in a git repository.")
+		return "", "", false
+	}
+	return loc.WorktreeRoot, currentRepo.Identifier, true
 }
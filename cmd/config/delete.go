@@ -12,7 +12,7 @@ var deleteCommand = &cobra.Command{
 	Use:     "delete",
 	Aliases: []string{"rm", "remove"},
 	Short:   "Delete configuration values",
-	Long:    `Delete configuration values such as search groups or aliases.`,
+	Long:    `Delete configuration values such as search groups, aliases, entry directories or on-enter commands.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
@@ -23,6 +23,12 @@ var deleteGroupCommand = &cobra.Command{
 	Short: "Delete a search group",
 	Long:  `Remove a search group by path or index.`,
 	Args:  cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return config.GetSearchFolders(), cobra.ShellCompDirectiveNoFileComp
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// Check if it's an index
 		if idx, err := strconv.Atoi(args[0]); err == nil {
@@ -38,6 +44,16 @@ var deleteAliasCommand = &cobra.Command{
 	Short: "Delete an alias",
 	Long:  `Remove an alias by its short name.`,
 	Args:  cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		completions := []string{}
+		for short, expanded := range config.GetAliases() {
+			completions = append(completions, short+"\t"+expanded)
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		config.DeleteAlias(args[0])
 	},
@@ -48,6 +64,12 @@ var deleteProtectedCommand = &cobra.Command{
 	Short: "Delete a protected branch",
 	Long:  `Remove a branch from the global protected branches list.`,
 	Args:  cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return config.GetGlobalPinnedBranches(), cobra.ShellCompDirectiveNoFileComp
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := config.DeleteGlobalPinnedBranch(args[0]); err != nil {
 			log.Error("Failed to delete protected branch: {}", err)
@@ -55,8 +77,67 @@ var deleteProtectedCommand = &cobra.Command{
 	},
 }
 
+var deleteEntryCommand = &cobra.Command{
+	Use:   "entry",
+	Short: "Delete the current repository's entry directory",
+	Long:  `Make jumping to the current repository land in its root again.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, identifier, ok := currentRepoRoot()
+		if !ok {
+			return
+		}
+		if err := config.DeleteRepoEntry(identifier); err != nil {
+			log.Error("Failed to delete entry: {}", err)
+		}
+	},
+}
+
+var deleteOnEnterCommand = &cobra.Command{
+	Use:   "on-enter",
+	Short: "Delete the current repository's on-enter command",
+	Long:  `Stop running a command after jumping to the current repository.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, identifier, ok := currentRepoRoot()
+		if !ok {
+			return
+		}
+		if err := config.DeleteRepoOnEnter(identifier); err != nil {
+			log.Error("Failed to delete on-enter command: {}", err)
+		}
+	},
+}
+
+var deleteTagCommand = &cobra.Command{
+	Use:   "tag <tag>...",
+	Short: "Remove tags from the current repository",
+	Args:  cobra.MinimumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		_, identifier, ok := currentRepoRoot()
+		if !ok {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return config.GetTagsForRepo(identifier), cobra.ShellCompDirectiveNoFileComp
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		_, identifier, ok := currentRepoRoot()
+		if !ok {
+			return
+		}
+		for _, tag := range args {
+			if err := config.DeleteRepoTag(identifier, tag); err != nil {
+				log.Error("Failed to delete tag: {}", err)
+			}
+		}
+	},
+}
+
 func init() {
 	deleteCommand.AddCommand(deleteGroupCommand)
 	deleteCommand.AddCommand(deleteAliasCommand)
 	deleteCommand.AddCommand(deleteProtectedCommand)
+	deleteCommand.AddCommand(deleteEntryCommand)
+	deleteCommand.AddCommand(deleteOnEnterCommand)
+	deleteCommand.AddCommand(deleteTagCommand)
 }
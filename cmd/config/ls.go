@@ -1,6 +1,8 @@
 package config
 
 import (
+	"strings"
+
 	"github.com/kernelle-soft/gimme/internal/config"
 	"github.com/kernelle-soft/gimme/internal/log"
 	"github.com/spf13/cobra"
@@ -54,11 +56,43 @@ var lsAliasCommand = &cobra.Command{
 	},
 }
 
+var lsEntryCommand = &cobra.Command{
+	Use:     "entry",
+	Aliases: []string{"entries"},
+	Short:   "List entry directories",
+	Long:    `List the subdirectories that jumps to each repository land in.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		showEntries()
+	},
+}
+
+var lsOnEnterCommand = &cobra.Command{
+	Use:   "on-enter",
+	Short: "List on-enter commands",
+	Long:  `List the commands that run after jumping to each repository.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		showOnEnters()
+	},
+}
+
+var lsTagCommand = &cobra.Command{
+	Use:     "tag",
+	Aliases: []string{"tags"},
+	Short:   "List repository tags",
+	Long:    `List the tags of each repository.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		showTags()
+	},
+}
+
 func init() {
 	lsCommand.AddCommand(lsGroupCommand)
 	lsCommand.AddCommand(lsPinnedRepoCommand)
 	lsCommand.AddCommand(lsPinnedBranchCommand)
 	lsCommand.AddCommand(lsAliasCommand)
+	lsCommand.AddCommand(lsEntryCommand)
+	lsCommand.AddCommand(lsOnEnterCommand)
+	lsCommand.AddCommand(lsTagCommand)
 }
 
 var lsRun = func(cmd *cobra.Command, args []string) {
@@ -70,6 +104,12 @@ var lsRun = func(cmd *cobra.Command, args []string) {
 	showPinnedBranches()
 	log.Print("")
 	showAliases()
+	log.Print("")
+	showEntries()
+	log.Print("")
+	showOnEnters()
+	log.Print("")
+	showTags()
 }
 
 func showGroups() {
@@ -131,3 +171,39 @@ func showAliases() {
 		log.Print("  {} -> {}", short, expanded)
 	}
 }
+
+func showEntries() {
+	entries := config.GetRepoEntries()
+	log.Print("Entry Directories:")
+	if len(entries) == 0 {
+		log.Print("  (none configured)")
+		return
+	}
+	for repo, entry := range entries {
+		log.Print("  {} -> {}", repo, entry)
+	}
+}
+
+func showOnEnters() {
+	commands := config.GetRepoOnEnters()
+	log.Print("On-enter Commands:")
+	if len(commands) == 0 {
+		log.Print("  (none configured)")
+		return
+	}
+	for repo, command := range commands {
+		log.Print("  {} -> {}", repo, command)
+	}
+}
+
+func showTags() {
+	tags := config.GetRepoTags()
+	log.Print("Tags:")
+	if len(tags) == 0 {
+		log.Print("  (none configured)")
+		return
+	}
+	for repo, repoTags := range tags {
+		log.Print("  {} -> {}", repo, strings.Join(repoTags, ", "))
+	}
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/kernelle-soft/gimme/internal/directive"
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/parallel"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	execSelection    selection
+	execJobsFlag     int
+	execGroupedFlag  bool
+	execFailFastFlag bool
+)
+
+var execCommand = &cobra.Command{
+	Use:   "exec [flags] [--] <command> [args...]",
+	Short: "Run a command in many repositories",
+	Long: `Run a command in the root of every selected repository, several at once. A single argument is run by sh, so it may use pipes and variables; several are run as a program and its arguments.
+
+Each line of output is prefixed with the repository it came from. With --grouped, a repository's output is held back and printed in one piece once it finishes. Afterwards, the repositories whose command failed are listed with their exit codes, and gimme exits with 1 if there were any.
+
+The command runs with GIMME_REPO set to the repository's identifier and GIMME_BRANCH to its branch.
+
+Output goes to stdout, except under the shell wrapper from 'gimme init', which reads gimme's stdout for directives; there it goes to stderr, so pipe it with 2>&1, e.g. 'gimme exec -- git log -1 2>&1 | grep fix'.
+
+Example:
+	gimme exec -- make test                       # every repository
+	gimme exec -g ~/work -p -- git pull           # pinned repositories in ~/work
+	gimme exec -q api 'git log -1 --oneline | cat'
+	gimme list --paths kern | gimme exec --stdin -- go build ./...`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  execRun,
+}
+
+func init() {
+	addSelectionFlags(execCommand, &execSelection)
+	execCommand.Flags().IntVarP(&execJobsFlag, "jobs", "j", 0, "How many repositories to run in at once (default search.parallelism)")
+	execCommand.Flags().BoolVar(&execGroupedFlag, "grouped", false, "Print each repository's output in one piece when it finishes")
+	execCommand.Flags().BoolVar(&execFailFastFlag, "fail-fast", false, "Stop at the first failure, interrupting commands still running")
+	// Flags after the command belong to it, not to gimme
+	execCommand.Flags().SetInterspersed(false)
+}
+
+// execResult is how the command went in one repository.
+type execResult struct {
+	repo     repo.Repo
+	exitCode int
+	err      error // the command couldn't be run at all
+	skipped  bool  // never started because of --fail-fast
+}
+
+func (r execResult) failed() bool {
+	return r.err != nil || r.exitCode != 0
+}
+
+var execRun = func(cmd *cobra.Command, args []string) {
+	repos, err := execSelection.repos(cmd.InOrStdin())
+	if err != nil {
+		log.Error("Could not select repositories: {}", err)
+		exitCode = 1
+		return
+	}
+	if len(repos) == 0 {
+		log.Print("No repositories selected.")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	out := commandOutput()
+	width := 0
+	for _, r := range repos {
+		width = max(width, len(r.Name))
+	}
+
+	results := parallel.Map(repos, jobs(execJobsFlag), func(r repo.Repo) execResult {
+		if ctx.Err() != nil {
+			return execResult{repo: r, skipped: true}
+		}
+
+		var result execResult
+		if execGroupedFlag {
+			var buf bytes.Buffer
+			result = runInRepo(ctx, r, args, &buf)
+			mu.Lock()
+			fmt.Fprintf(out, "==> %s (%s)\n%s", r.Name, describeExit(result), buf.Bytes())
+			if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+				fmt.Fprintln(out)
+			}
+			mu.Unlock()
+		} else {
+			w := &prefixWriter{mu: &mu, out: out, prefix: pad(r.Name, width) + " | "}
+			result = runInRepo(ctx, r, args, w)
+			w.Flush()
+		}
+
+		if execFailFastFlag && result.failed() {
+			cancel()
+		}
+		return result
+	})
+
+	printExecSummary(results)
+}
+
+// commandOutput returns where to send the output of commands gimme runs:
+// stdout, unless a shell wrapper is reading it for directives.
+func commandOutput() *os.File {
+	if os.Getenv(directive.ProtocolEnv) != "" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// runInRepo runs the command in r's root, sending its output to out.
+func runInRepo(ctx context.Context, r repo.Repo, args []string, out io.Writer) execResult {
+	var c *exec.Cmd
+	if len(args) == 1 {
+		c = exec.CommandContext(ctx, "sh", "-c", args[0])
+	} else {
+		c = exec.CommandContext(ctx, args[0], args[1:]...)
+	}
+	c.Dir = r.Path
+	c.Env = append(os.Environ(), directive.RepoEnv+"="+r.Identifier, directive.BranchEnv+"="+r.CurrentBranch())
+	c.Stdout = out
+	c.Stderr = out
+
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return execResult{repo: r, exitCode: exitErr.ExitCode()}
+	}
+	return execResult{repo: r, err: err}
+}
+
+// describeExit says how a command ended, e.g. "exit 0" or "interrupted".
+func describeExit(result execResult) string {
+	switch {
+	case result.err != nil:
+		return result.err.Error()
+	case result.exitCode < 0:
+		return "interrupted"
+	default:
+		return fmt.Sprintf("exit %d", result.exitCode)
+	}
+}
+
+// printExecSummary lists the repositories where the command failed or never
+// ran, and sets gimme's exit code if there were any.
+func printExecSummary(results []execResult) {
+	failed, skipped := []execResult{}, 0
+	for _, result := range results {
+		switch {
+		case result.skipped:
+			skipped++
+		case result.failed():
+			failed = append(failed, result)
+		}
+	}
+
+	log.Print("")
+	if len(failed) == 0 && skipped == 0 {
+		log.Print("Succeeded in all {} repositories.", len(results))
+		return
+	}
+
+	exitCode = 1
+	log.Print("Failed in {} of {} repositories:", len(failed), len(results))
+	for _, result := range failed {
+		log.Print("  {} ({})", result.repo.Name, describeExit(result))
+	}
+	if skipped > 0 {
+		log.Print("Skipped {} repositories after the first failure.", skipped)
+	}
+}
+
+// prefixWriter writes each line it's given to out with a prefix. Writers
+// sharing a mutex never interleave within a line.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	line   []byte // an incomplete line waiting for its newline
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.line = append(w.line, p...)
+	for {
+		i := bytes.IndexByte(w.line, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.writeLine(w.line[:i+1])
+		w.line = w.line[i+1:]
+	}
+}
+
+// Flush writes out a final line that didn't end in a newline.
+func (w *prefixWriter) Flush() {
+	if len(w.line) > 0 {
+		w.writeLine(append(w.line, '\n'))
+		w.line = nil
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintf(w.out, "%s%s", w.prefix, line)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/parallel"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fetchSelection selection
+	fetchPruneFlag bool
+	fetchJobsFlag  int
+)
+
+var fetchCommand = &cobra.Command{
+	Use:   "fetch",
+	Short: "Fetch every repository's remotes",
+	Long: `Fetch the remotes of every selected repository, several at once, and report which ones had new commits.
+
+Example:
+	gimme fetch              # every repository
+	gimme fetch --prune -p   # pinned repositories, deleting branches gone from the remote`,
+	Args: cobra.NoArgs,
+	Run:  fetchRun,
+}
+
+func init() {
+	addSelectionFlags(fetchCommand, &fetchSelection)
+	fetchCommand.Flags().BoolVar(&fetchPruneFlag, "prune", false, "Delete remote-tracking branches whose branch is gone from the remote")
+	fetchCommand.Flags().IntVarP(&fetchJobsFlag, "jobs", "j", 0, "How many repositories to fetch at once (default search.parallelism)")
+}
+
+// fetchOutcome is how fetching one repository went.
+type fetchOutcome struct {
+	repo   repo.Repo
+	result repo.FetchResult
+	err    error
+}
+
+var fetchRun = func(cmd *cobra.Command, args []string) {
+	repos, err := fetchSelection.repos(cmd.InOrStdin())
+	if err != nil {
+		log.Error("Could not select repositories: {}", err)
+		exitCode = 1
+		return
+	}
+	if len(repos) == 0 {
+		log.Print("No repositories selected.")
+		return
+	}
+
+	outcomes := parallel.Map(repos, jobs(fetchJobsFlag), func(r repo.Repo) fetchOutcome {
+		result, err := r.Fetch(fetchPruneFlag)
+		return fetchOutcome{repo: r, result: result, err: err}
+	})
+
+	width := 0
+	for _, o := range outcomes {
+		width = max(width, len(o.repo.Name))
+	}
+
+	var updated, upToDate, failed int
+	for _, o := range outcomes {
+		switch {
+		case o.err != nil:
+			failed++
+			log.Print("{}  failed: {}", pad(o.repo.Name, width), o.err)
+		case len(o.result.Updated) == 0 && len(o.result.Pruned) == 0:
+			upToDate++
+			log.Print("{}  up to date", pad(o.repo.Name, width))
+		default:
+			updated++
+			log.Print("{}  {}", pad(o.repo.Name, width), describeFetch(o.result))
+		}
+	}
+
+	log.Print("")
+	log.Print("{} repositories: {} updated, {} up to date, {} failed", len(outcomes), updated, upToDate, failed)
+	if failed > 0 {
+		exitCode = 1
+	}
+}
+
+// describeFetch summarizes what a fetch changed, e.g. "2 branches updated,
+// 1 pruned".
+func describeFetch(result repo.FetchResult) string {
+	description := ""
+	switch len(result.Updated) {
+	case 0:
+	case 1:
+		description = result.Updated[0] + " updated"
+	default:
+		description = fmt.Sprintf("%d branches updated", len(result.Updated))
+	}
+
+	if len(result.Pruned) > 0 {
+		if description != "" {
+			description += ", "
+		}
+		description += fmt.Sprintf("%d pruned", len(result.Pruned))
+	}
+	return description
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/kernelle-soft/gimme/internal/frecency"
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/path"
+	"github.com/spf13/cobra"
+)
+
+var frecencyCommand = &cobra.Command{
+	Use:   "frecency",
+	Short: "Inspect or forget jump history used for ranking",
+	Long: `Inspect or forget the jump history gimme uses to rank search results.
+
+Every successful jump is recorded with a visit count and timestamp. Repositories you visit often and recently rank higher when jumping. Counts decay over time so old favorites make room for what you use now.
+
+The history is kept in $XDG_STATE_HOME/gimme (default ~/.local/state/gimme), separate from .gimme.config.yaml.
+
+  gimme frecency ls             - list entries, highest score first
+  gimme frecency forget <path>  - forget a path
+  gimme frecency clear          - forget everything`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var frecencyLsCommand = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List recorded jumps",
+	Long:    `List recorded jumps, highest score first.`,
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := frecency.Load()
+		if err != nil {
+			log.Error("Could not load jump history: {}", err)
+			return
+		}
+
+		now := time.Now()
+		entries := store.Entries(now)
+		if len(entries) == 0 {
+			log.Print("No jumps recorded.")
+			return
+		}
+		for _, e := range entries {
+			log.Print("{}  {} (visits: {}, last: {})",
+				fmt.Sprintf("%8.1f", e.Score(now)), e.Path, fmt.Sprintf("%.1f", e.Count), e.LastAccess.Format(time.DateTime))
+		}
+	},
+}
+
+var frecencyForgetCommand = &cobra.Command{
+	Use:   "forget <path>",
+	Short: "Forget a recorded path",
+	Long:  `Remove a path from the jump history so it no longer gets a ranking boost.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := frecency.Load()
+		if err != nil {
+			log.Error("Could not load jump history: {}", err)
+			return
+		}
+
+		target, err := path.Normalize(args[0])
+		if err == nil {
+			target, err = filepath.Abs(target)
+		}
+		if err != nil {
+			log.Error("Error parsing path \"{}\". Error: {}", args[0], err)
+			return
+		}
+
+		if !store.Forget(target) {
+			log.Print("Path not found in jump history: \"{}\".", target)
+			return
+		}
+		if err := store.Save(); err != nil {
+			log.Error("Error saving jump history. Error: {}", err)
+			return
+		}
+		log.Print("Forgot \"{}\".", target)
+	},
+}
+
+var frecencyClearCommand = &cobra.Command{
+	Use:   "clear",
+	Short: "Forget all recorded jumps",
+	Long:  `Remove every entry from the jump history.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := frecency.Load()
+		if err != nil {
+			log.Error("Could not load jump history: {}", err)
+			return
+		}
+
+		store.Clear()
+		if err := store.Save(); err != nil {
+			log.Error("Error saving jump history. Error: {}", err)
+			return
+		}
+		log.Print("Cleared jump history.")
+	},
+}
+
+func init() {
+	frecencyCommand.AddCommand(frecencyLsCommand)
+	frecencyCommand.AddCommand(frecencyForgetCommand)
+	frecencyCommand.AddCommand(frecencyClearCommand)
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/kernelle-soft/gimme/internal/history"
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyGlobalFlag bool
+	historyLimitFlag  int
+)
+
+var historyCommand = &cobra.Command{
+	Use:   "history",
+	Short: "List recently visited locations",
+	Long: `List the locations you recently jumped from and to, most recent first, leaving out directories that no longer exist. Use the number in front of a location with 'gimme back <n>' to return to it.
+
+Each shell keeps its own history when gimme's shell integration is loaded. Use --global to see locations from every shell.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := history.Load()
+		if err != nil {
+			log.Error("Could not load directory history: {}", err)
+			return
+		}
+
+		session := history.Session()
+		if historyGlobalFlag {
+			session = ""
+		}
+
+		cwd, _ := os.Getwd()
+		recent := store.Reachable(session, cwd)
+		if len(recent) == 0 {
+			log.Print("No history recorded.")
+			return
+		}
+
+		if historyLimitFlag > 0 && len(recent) > historyLimitFlag {
+			recent = recent[:historyLimitFlag]
+		}
+		for i, e := range recent {
+			log.Print("{}  {} ({})", i+1, e.Path, e.Time.Format(time.DateTime))
+		}
+	},
+}
+
+var historyClearCommand = &cobra.Command{
+	Use:   "clear",
+	Short: "Forget visited locations",
+	Long:  `Forget this shell's history, or with --global, the history of every shell.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := history.Load()
+		if err != nil {
+			log.Error("Could not load directory history: {}", err)
+			return
+		}
+
+		session := history.Session()
+		if historyGlobalFlag {
+			session = ""
+		}
+
+		store.Clear(session)
+		if err := store.Save(); err != nil {
+			log.Error("Error saving directory history. Error: {}", err)
+			return
+		}
+		log.Print("Cleared directory history.")
+	},
+}
+
+func init() {
+	historyCommand.PersistentFlags().BoolVarP(&historyGlobalFlag, "global", "g", false, "Use the history of every shell")
+	historyCommand.Flags().IntVarP(&historyLimitFlag, "limit", "n", 20, "Show at most this many locations (0 for all)")
+	historyCommand.AddCommand(historyClearCommand)
+}
@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/muesli/reflow/indent"
 	"github.com/muesli/reflow/wordwrap"
 	"github.com/spf13/cobra"
 
 	configcmd "github.com/kernelle-soft/gimme/cmd/config"
+	indexcmd "github.com/kernelle-soft/gimme/cmd/index"
+	worktreecmd "github.com/kernelle-soft/gimme/cmd/worktree"
 	"github.com/kernelle-soft/gimme/internal/config"
 )
 
@@ -20,20 +24,48 @@ var root = &cobra.Command{
 	Long:  indent.String(wordwrap.String(`The multi-repo manager for professional developers. Gimme is a tool that helps you streamline the process of hopping from project to project, branch to branch, and worktree to worktree.`, 80), 2),
 	Run:   jumpRun,
 	Args:  cobra.MaximumNArgs(1),
+
+	ValidArgsFunction: completeRepos,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		config.Load()
 	},
 }
 
 func init() {
+	addJumpFlags(root)
+
 	root.AddCommand(jumpToRepoCommand)
+	root.AddCommand(cloneCommand)
 	root.AddCommand(listCommand)
+	root.AddCommand(statusCommand)
+	root.AddCommand(execCommand)
+	root.AddCommand(taskCommand)
+	root.AddCommand(trustCommand)
+	root.AddCommand(untrustCommand)
+	root.AddCommand(fetchCommand)
+	root.AddCommand(pullCommand)
+	root.AddCommand(syncCommand)
+	root.AddCommand(syncWorkspaceCommand)
+	root.AddCommand(snapshotCommand)
 	root.AddCommand(pinCommand)
 	root.AddCommand(unpinCommand)
 	root.AddCommand(cleanCommand)
+	root.AddCommand(frecencyCommand)
+	root.AddCommand(backCommand)
+	root.AddCommand(historyCommand)
+	root.AddCommand(initCommand)
 	root.AddCommand(configcmd.Command)
+	root.AddCommand(indexcmd.Command)
+	root.AddCommand(worktreecmd.Command)
 }
 
+// exitCode is the status gimme exits with once the command has run. Commands
+// that work through many repositories set it when some of them failed.
+var exitCode int
+
 func Execute() {
 	root.Execute()
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
 }
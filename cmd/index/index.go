@@ -0,0 +1,70 @@
+package index
+
+import (
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var Command = &cobra.Command{
+	Use:   "index",
+	Short: "Manage the repository index",
+	Long: `Manages the on-disk index of discovered repositories.
+
+Searching every search group on each invocation is slow, so gimme remembers which directories contain repositories and only re-reads directories whose modification time changed. When a search finds nothing in the index, gimme falls back to a live scan.
+
+The index is kept in $XDG_CACHE_HOME/gimme (default ~/.cache/gimme) and is safe to delete.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var rebuildCommand = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Rebuild the repository index",
+	Long:  `Discard the repository index and rescan every search group.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		count, err := search.RebuildIndex()
+		if err != nil {
+			log.Error("Failed to rebuild index: {}", err)
+			return
+		}
+		log.Print("Indexed {} repositories.", count)
+	},
+}
+
+var clearCommand = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete the repository index",
+	Long:  `Delete the repository index. It is rebuilt on the next search.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := search.ClearIndex(); err != nil {
+			log.Error("Failed to clear index: {}", err)
+			return
+		}
+		log.Print("Cleared repository index.")
+	},
+}
+
+var pathCommand = &cobra.Command{
+	Use:   "path",
+	Short: "Show where the index is stored",
+	Long:  `Show the location of the repository index file.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := search.IndexPath()
+		if err != nil {
+			log.Error("Could not locate index: {}", err)
+			return
+		}
+		log.Print("{}", path)
+	},
+}
+
+func init() {
+	Command.AddCommand(rebuildCommand)
+	Command.AddCommand(clearCommand)
+	Command.AddCommand(pathCommand)
+}
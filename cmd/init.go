@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+var initNameFlag string
+
+var initCommand = &cobra.Command{
+	Use:   "init <shell>",
+	Short: "Print shell integration",
+	Long: `Print the shell function that lets gimme change your shell's directory and environment. Supported shells: ` + strings.Join(shell.Shells(), ", ") + `.
+
+Load it from your shell's startup file:
+
+  bash:    eval "$(gimme init bash)"          # ~/.bashrc
+  zsh:     eval "$(gimme init zsh)"           # ~/.zshrc
+  fish:    gimme init fish | source           # ~/.config/fish/config.fish
+  nu:      gimme init nu | save -f ~/.config/nushell/gimme.nu
+           source ~/.config/nushell/gimme.nu  # config.nu
+  elvish:  eval (gimme init elvish | slurp)   # ~/.config/elvish/rc.elv
+
+After a jump the function sets GIMME_REPO and GIMME_BRANCH to the repository and branch you landed in, and runs the repository's on-enter command if it has one (see 'gimme config add on-enter'; one from a .gimme.yaml only runs once you've trusted the file with 'gimme trust'). Nushell shows on-enter commands instead of running them.
+
+Use --name to call the function something other than 'gimme', e.g. 'gimme init zsh --name g'.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: shell.Shells(),
+	Run: func(cmd *cobra.Command, args []string) {
+		script, err := shell.Script(args[0], shell.Options{Name: initNameFlag})
+		if err != nil {
+			log.Error("Could not generate shell integration: {}", err)
+			return
+		}
+		fmt.Fprint(cmd.OutOrStdout(), script)
+	},
+}
+
+func init() {
+	initCommand.Flags().StringVar(&initNameFlag, "name", shell.DefaultName, "Name of the shell function")
+}
@@ -1,11 +1,19 @@
 package cmd
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/directive"
+	"github.com/kernelle-soft/gimme/internal/history"
+	"github.com/kernelle-soft/gimme/internal/hook"
 	"github.com/kernelle-soft/gimme/internal/log"
 	"github.com/kernelle-soft/gimme/internal/path"
+	"github.com/kernelle-soft/gimme/internal/picker"
+	"github.com/kernelle-soft/gimme/internal/repo"
 	"github.com/kernelle-soft/gimme/internal/search"
 	"github.com/spf13/cobra"
 )
@@ -16,14 +24,54 @@ var jumpToRepoCommand = &cobra.Command{
 	Long: `Jump to a project's root directory. 
 	
 	This command will change the current working directory to the root directory of the project.
-	The specified string will be used to search recursively for the project's directory name and hit the first match, including partial matches.
+	The specified string will be used to search recursively for the project's directory name and hit the best match.
+	Matches are ranked exact > prefix > word boundary > substring > subsequence, and pinned repositories get a boost within each rank.
+	Matching is case-insensitive unless the query contains an uppercase letter.
 	
 	Example:
 	gimme jump kernelle # jumps to the kernelle project's root directory
 	gimme kernelle # same as above. 'jump' is optional and simply intended for disambiguation if ever necessary.
     gimme kern # jumps to the kernelle project's root directory because 'kern' is a partial match for 'kernelle'.
+	gimme api # prefers 'api' over 'legacy-api' because exact matches rank first.
+	gimme ap # several repositories match, so a picker lists them to choose from. Pass --first to take the best match instead.
+	gimme acme/api # picks the 'api' whose remote (e.g. github.com/acme/api) or path contains 'acme'.
+	gimme gitlab.com/acme # matches every repository of that owner on that host.
+	gimme kernelle@feature-x # jumps to the worktree of kernelle that has feature-x checked out.
+	gimme @feature-x # same as above, for the repository you're currently in.
+	gimme kern/int/search # jumps to kernelle/internal/search. Segments after the ones naming the repository are fuzzy-matched against subdirectories.
+	gimme kernelle@feature-x/internal # the same works inside a branch's worktree.
+
+	Jumps to a repository land in its entry directory if one is set (see 'gimme config add entry'). End the query with a slash, e.g. 'gimme kernelle/', to land in the root instead.
+
+	If no worktree has the branch checked out, --worktree creates one (see 'gimme worktree') and --checkout checks the branch out in the main working tree if it has no local changes. Set worktrees.on-missing-branch to "worktree" or "checkout" to make either the default.
+
+	gimme - # jumps back to where you were before the last jump. See 'gimme back' and 'gimme history'.
+
+	Every jump is recorded, and repositories you visit often and recently rank higher. See 'gimme frecency'.
+
+	Jumps to a repository run its post-jump hooks, if any are configured. See 'hooks' in the config.
 	`,
-	Run: jumpRun,
+	Run:               jumpRun,
+	ValidArgsFunction: completeRepos,
+}
+
+var (
+	jumpWorktreeFlag bool
+	jumpCheckoutFlag bool
+	jumpFirstFlag    bool
+)
+
+func init() {
+	addJumpFlags(jumpToRepoCommand)
+}
+
+// addJumpFlags registers the jump flags on cmd. Both the root command and
+// 'jump' run jumpRun, so both need them.
+func addJumpFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&jumpWorktreeFlag, "worktree", "w", false, "Create a worktree for repo@branch if none has the branch checked out")
+	cmd.Flags().BoolVarP(&jumpCheckoutFlag, "checkout", "c", false, "Check out repo@branch in the main working tree if it's clean")
+	cmd.Flags().BoolVarP(&jumpFirstFlag, "first", "1", false, "Take the best match instead of asking when the query is ambiguous")
+	cmd.MarkFlagsMutuallyExclusive("worktree", "checkout")
 }
 
 var jumpRun = func(cmd *cobra.Command, args []string) {
@@ -32,7 +80,13 @@ var jumpRun = func(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	query := args[0]
+	// "gimme -" goes back like "cd -"
+	if args[0] == "-" {
+		goBack(1)
+		return
+	}
+
+	query, branch := search.SplitBranch(args[0])
 
 	// Expand alias if one exists
 	aliases := config.GetAliases()
@@ -42,21 +96,232 @@ var jumpRun = func(cmd *cobra.Command, args []string) {
 
 	// Check if query is a direct path (alias may have expanded to a path)
 	normalizedQuery, _ := path.Normalize(query)
-	if info, err := os.Stat(normalizedQuery); err == nil && info.IsDir() {
-		log.ToStdout(normalizedQuery)
+	if isDir(normalizedQuery) && branch == "" {
+		search.Land(normalizedQuery, normalizedQuery)
+		runPostJump(normalizedQuery)
 		return
 	}
 
-	// Search for repositories matching the query
-	found := search.Repositories(search.ForRepo(query))
-	if len(found) == 0 {
-		log.Print("No repositories found for \"{}\".", query)
+	// repo/sub/path: leading segments name the repository, the rest is a
+	// subdirectory inside it. A trailing slash lands in the root.
+	name, subpath, _ := search.SplitSubpath(query)
+	explicit := strings.HasSuffix(query, "/")
+	if isDir(normalizedQuery) {
+		name, subpath, explicit = normalizedQuery, "", false
+	} else if expanded, ok := aliases[name]; ok {
+		name = expanded
+	}
+
+	var found *repo.Repo
+	normalizedName, _ := path.Normalize(name)
+	switch {
+	case name == "" && branch != "":
+		// "@branch" means the repository we're in
+		cwd, err := os.Getwd()
+		if err != nil {
+			log.Error("Could not determine current directory: {}", err)
+			return
+		}
+		found = findRepoAt(cwd)
+	case isDir(normalizedName):
+		if branch == "" {
+			jumpInto(normalizedName, subpath, "")
+			return
+		}
+		found = findRepoAt(normalizedName)
+	default:
+		// Search for repositories matching the query, best match first
+		if subpath != "" {
+			name += "/" + subpath
+		}
+		var matches []repo.Repo
+		matches, subpath = search.RepositoriesWithSubpath(name)
+		if len(matches) == 0 {
+			log.Print("No repositories found for \"{}\".", name)
+			return
+		}
+		explicit = explicit || subpath != ""
+		if found = pickRepo(strings.TrimSuffix(name, "/"+subpath), matches); found == nil {
+			return
+		}
+	}
+	if found == nil {
 		return
 	}
 
-	// Sort by pins so pinned repos are prioritized
-	search.SortByPins(found)
+	root := found.Path
+	if branch != "" {
+		var branchSubpath string
+		rest := branch
+		branch, branchSubpath = search.SplitBranchSubpath(found, rest)
+		subpath = filepath.Join(subpath, branchSubpath)
+		explicit = explicit || branchSubpath != "" || strings.HasSuffix(rest, "/")
+
+		var ok bool
+		if root, ok = branchRoot(found, branch); !ok {
+			return
+		}
+	}
+
+	entry := ""
+	if !explicit {
+		entry = search.SettingsFor(found.Identifier, root).Entry
+	}
+	jumpInto(root, subpath, entry)
+}
+
+// jumpInto jumps to subpath inside root, resolving each segment fuzzily. With
+// no subpath it lands in the entry directory, if one is set and still exists.
+// The jump is recorded against root, since that's what searches rank.
+func jumpInto(root, subpath, entry string) {
+	target := root
+	if subpath != "" {
+		resolved, err := search.ResolveSubpath(root, subpath)
+		if err != nil {
+			log.Print("Could not resolve \"{}\": {}.", subpath, err)
+			return
+		}
+		target = resolved
+	} else if entry != "" {
+		if dir := filepath.Join(root, entry); !path.IsWithin(dir, root) {
+			log.Warning("Entry directory \"{}\" is outside \"{}\".", entry, root)
+		} else if isDir(dir) {
+			target = dir
+		} else {
+			log.Warning("Entry directory \"{}\" doesn't exist in \"{}\".", entry, root)
+		}
+	}
+
+	search.Land(root, target)
+	runPostJump(root)
+}
+
+// runPostJump runs the post-jump hooks of the repository dir is in, if any.
+// A failing hook is only warned about; the jump stands.
+func runPostJump(dir string) {
+	r := search.FindRepoForPath(dir)
+	if r == nil {
+		return
+	}
+
+	target := hook.Target{Path: r.Path, Identifier: r.Identifier, Branch: r.CurrentBranch()}
+	if loc, err := repo.Locate(dir); err == nil {
+		target.Path = loc.WorktreeRoot
+	}
+	if err := hook.Run(hook.PostJump, target); err != nil {
+		log.Warning("{}.", err)
+	}
+}
 
-	// Found match.
-	log.ToStdout(found[0].Path)
+// pickRepo chooses between the repositories a query matched. The best match is
+// taken when it's the only one, when it's the only exact match, with --first,
+// or when there's no terminal to ask on; otherwise the user picks. Returns nil
+// if the user cancels.
+func pickRepo(query string, matches []repo.Repo) *repo.Repo {
+	if len(matches) == 1 || jumpFirstFlag || search.IsUniqueExact(query, matches) || !picker.Available() {
+		return &matches[0]
+	}
+
+	items := make([]picker.Item, len(matches))
+	for i, r := range matches {
+		items[i] = picker.Item{Label: r.Name, Detail: repoDetail(r)}
+	}
+
+	chosen, err := picker.Pick("gimme> ", query, items, func(query string, i int) int {
+		return search.MatchRepo(query, matches[i].Name, matches[i].Identifier, matches[i].Path)
+	})
+	if errors.Is(err, picker.ErrCancelled) {
+		return nil
+	}
+	if err != nil {
+		log.Debug("Picker unavailable, taking the best match: {}", err)
+		return &matches[0]
+	}
+	return &matches[chosen]
+}
+
+// repoDetail describes a repository in the picker: its branch, pin and
+// worktree status, and where it lives.
+func repoDetail(r repo.Repo) string {
+	detail := []string{}
+	if branch := r.CurrentBranch(); branch != "" {
+		detail = append(detail, "("+branch+")")
+	}
+	if r.Pinned {
+		detail = append(detail, "[pinned]")
+	}
+	if r.IsWorktree() {
+		detail = append(detail, "[worktree of "+r.Parent.Name+"]")
+	}
+
+	location := r.Path
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(location, home+string(filepath.Separator)) {
+		location = "~" + location[len(home):]
+	}
+	return strings.Join(append(detail, location), " ")
+}
+
+// findRepoAt returns the repository containing dir, logging when there is none.
+func findRepoAt(dir string) *repo.Repo {
+	found := search.FindRepoForPath(dir)
+	if found == nil {
+		log.Print("\"{}\" is not in a git repository.", dir)
+	}
+	return found
+}
+
+// branchRoot returns the worktree of r that has branch checked out, creating
+// one or checking the branch out first if the flags or config say so.
+func branchRoot(r *repo.Repo, branch string) (string, bool) {
+	if r.Parent != nil {
+		r = r.Parent
+	}
+
+	onMissing := config.GetWorktreeOnMissingBranch()
+	if jumpWorktreeFlag {
+		onMissing = search.MissingBranchWorktree
+	} else if jumpCheckoutFlag {
+		onMissing = search.MissingBranchCheckout
+	}
+
+	target, err := search.ResolveBranch(r, branch, onMissing)
+	switch {
+	case errors.Is(err, search.ErrNoSuchBranch):
+		log.Print("No branch \"{}\" in {}.", branch, r.Name)
+		return "", false
+	case errors.Is(err, search.ErrBranchNotCheckedOut):
+		log.Print("Branch \"{}\" isn't checked out in any worktree of {}. Use --worktree to create one or --checkout to switch the main tree to it.", branch, r.Name)
+		return "", false
+	case errors.Is(err, search.ErrMainTreeDirty):
+		log.Print("Can't check out \"{}\": {} has local changes. Use --worktree instead.", branch, r.Name)
+		return "", false
+	case err != nil:
+		log.Error("Could not switch to branch \"{}\": {}", branch, err)
+		return "", false
+	}
+
+	if target.Created {
+		log.Print("Created worktree \"{}\" for branch \"{}\".", target.Path, branch)
+	} else if target.CheckedOut {
+		log.Print("Checked out \"{}\" in {}.", branch, r.Name)
+	}
+	return target.Path, true
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// land moves the shell to target and describes the repository it lands in.
+// See search.Landing.
+func land(target string) {
+	directive.Emit(search.Landing(target)...)
+}
+
+// recordVisit adds target to the directory history used by 'gimme back'.
+func recordVisit(target string) {
+	if err := history.Record(target); err != nil {
+		log.Warning("Could not record directory history: {}", err)
+	}
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
@@ -16,11 +17,13 @@ var (
 	listBranchFlag   bool
 	listMergedFlag   bool
 	listNoMergedFlag bool
+	listPathsFlag    bool
+	listSelectFlag   string
 )
 
 var listDescription = Description{
 	Short: "List this workstation's visible repositories",
-	Long:  "List this workstations's visible repositories. Lists repositories under each search group recursively. Use -b to list branches instead.",
+	Long:  "List this workstations's visible repositories. Lists repositories under each search group recursively. Use -b to list branches instead, or --paths to print just the repositories' paths on stdout, e.g. for 'gimme exec --stdin'. Use --select to list only repositories matching an expression such as 'tag:backend !dirty'.",
 }
 
 var listCommand = &cobra.Command{
@@ -35,11 +38,15 @@ func init() {
 	listCommand.Flags().BoolVarP(&listBranchFlag, "branch", "b", false, "List branches instead of repositories")
 	listCommand.Flags().BoolVar(&listMergedFlag, "merged", false, "Show only merged branches (requires -b)")
 	listCommand.Flags().BoolVar(&listNoMergedFlag, "no-merged", false, "Show only unmerged branches (requires -b)")
+	listCommand.Flags().BoolVar(&listPathsFlag, "paths", false, "Print only the paths of repositories, one per line on stdout")
+	listCommand.Flags().StringVarP(&listSelectFlag, "select", "s", "", selectUsage)
 }
 
 var listRun = func(cmd *cobra.Command, args []string) {
 	if listBranchFlag {
 		listBranches()
+	} else if listPathsFlag {
+		listPaths(cmd, args)
 	} else {
 		listRepos(args)
 	}
@@ -51,8 +58,13 @@ func listRepos(args []string) {
 		query = args[0]
 	}
 
+	selector, ok := parseSelect(listSelectFlag)
+	if !ok {
+		return
+	}
+
 	// Get all repos to find pinned ones
-	allRepos := search.Repositories(search.ForRepo(query))
+	allRepos := selector.Filter(search.Repositories(search.ForRepo(query)), jobs(0))
 
 	// Show pinned repos first as their own group
 	pinnedRepos := []repo.Repo{}
@@ -75,21 +87,44 @@ func listRepos(args []string) {
 	// Show repos by search folder
 	for _, folder := range config.GetSearchFolders() {
 		log.Print("{}/", folder)
-		repos := search.Repositories(search.RepoSearchOptions{
+		repos := selector.Filter(search.Repositories(search.RepoSearchOptions{
 			Query:         query,
 			SearchFolders: []string{folder},
-		})
+		}), jobs(0))
 
 		for _, r := range repos {
-			if r.Pinned {
+			switch {
+			case r.Pinned:
 				log.Print("  {} ({}) [pinned]", r.Name, r.CurrentBranch())
-			} else {
+			case r.IsWorktree():
+				log.Print("  {} ({}) [worktree of {}]", r.Name, r.CurrentBranch(), r.Parent.Name)
+			default:
 				log.Print("  {} ({})", r.Name, r.CurrentBranch())
 			}
 		}
 	}
 }
 
+// listPaths prints the path of every repository matching the query on stdout,
+// pinned repositories first, so it can be piped to other commands.
+func listPaths(cmd *cobra.Command, args []string) {
+	var query string
+	if len(args) > 0 {
+		query = args[0]
+	}
+
+	selector, ok := parseSelect(listSelectFlag)
+	if !ok {
+		return
+	}
+
+	repos := selector.Filter(search.Repositories(search.ForRepo(query)), jobs(0))
+	search.SortByPins(repos)
+	for _, r := range repos {
+		fmt.Fprintln(cmd.OutOrStdout(), r.Path)
+	}
+}
+
 func listBranches() {
 	// Get current working directory to determine which repo we're in
 	cwd, err := os.Getwd()
@@ -105,9 +140,9 @@ func listBranches() {
 		return
 	}
 
-	globalPins := config.GetGlobalPinnedBranches()
-	repoPins := config.GetRepoPinnedBranches()
-	repoPinnedBranches := repoPins[currentRepo.Identifier]
+	settings := search.SettingsFor(currentRepo.Identifier, currentRepo.Path)
+	protected := settings.Protected
+	repoPinnedBranches := settings.Pinned
 
 	branches := currentRepo.ListBranches()
 	currentBranch := currentRepo.CurrentBranch()
@@ -116,7 +151,7 @@ func listBranches() {
 
 	for _, branch := range branches {
 		// Check merged/unmerged filter
-		isMerged := currentRepo.IsMerged(branch, globalPins)
+		isMerged := currentRepo.IsMerged(branch, protected)
 
 		if listMergedFlag && !isMerged {
 			continue
@@ -133,7 +168,7 @@ func listBranches() {
 
 		// Pin status in square brackets
 		pinStatus := ""
-		if slice.Contains(globalPins, branch) {
+		if slice.Contains(protected, branch) {
 			pinStatus = " [protected]"
 		} else if slice.Contains(repoPinnedBranches, branch) {
 			pinStatus = " [pinned]"
@@ -141,7 +176,7 @@ func listBranches() {
 
 		// Other status indicators in parentheses
 		statusIndicators := []string{}
-		if isMerged && !slice.Contains(globalPins, branch) {
+		if isMerged && !slice.Contains(protected, branch) {
 			statusIndicators = append(statusIndicators, "merged")
 		}
 
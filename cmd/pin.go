@@ -24,7 +24,8 @@ Without -b flag: pins a repository so it appears at the top of search results.
 With -b flag: pins a branch in the current repo (protects from clean).
   gimme pin -b        - pin current branch
   gimme pin -b <name> - pin branch by name`,
-	Run: pinRun,
+	Run:               pinRun,
+	ValidArgsFunction: completePinArg,
 }
 
 func init() {
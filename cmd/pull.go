@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/parallel"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pullSelection  selection
+	pullFFOnlyFlag bool
+	pullJobsFlag   int
+)
+
+var pullCommand = &cobra.Command{
+	Use:   "pull",
+	Short: "Bring every repository's current branch up to date",
+	Long: `Fetch every selected repository and fast-forward its current branch to its upstream, several at once.
+
+Repositories with local changes, a detached HEAD or no upstream are skipped. A branch with commits of its own has diverged and is left alone; pass --ff-only=false to rebase those commits onto upstream instead. Rebases that conflict are aborted.
+
+Example:
+	gimme pull               # every repository
+	gimme pull -g ~/work     # repositories in the ~/work search group`,
+	Args: cobra.NoArgs,
+	Run:  pullRun,
+}
+
+func init() {
+	addSelectionFlags(pullCommand, &pullSelection)
+	pullCommand.Flags().BoolVar(&pullFFOnlyFlag, "ff-only", true, "Only fast-forward; leave diverged branches alone instead of rebasing them")
+	pullCommand.Flags().IntVarP(&pullJobsFlag, "jobs", "j", 0, "How many repositories to pull at once (default search.parallelism)")
+}
+
+// pullOutcome is how pulling one repository went.
+type pullOutcome struct {
+	repo   repo.Repo
+	result repo.PullResult
+	err    error
+}
+
+var pullRun = func(cmd *cobra.Command, args []string) {
+	repos, err := pullSelection.repos(cmd.InOrStdin())
+	if err != nil {
+		log.Error("Could not select repositories: {}", err)
+		exitCode = 1
+		return
+	}
+	if len(repos) == 0 {
+		log.Print("No repositories selected.")
+		return
+	}
+
+	outcomes := parallel.Map(repos, jobs(pullJobsFlag), func(r repo.Repo) pullOutcome {
+		result, err := r.Pull(!pullFFOnlyFlag)
+		return pullOutcome{repo: r, result: result, err: err}
+	})
+
+	width := 0
+	for _, o := range outcomes {
+		width = max(width, len(o.repo.Name))
+	}
+
+	var advanced, upToDate, diverged, skipped, failed int
+	for _, o := range outcomes {
+		name := pad(o.repo.Name, width)
+		switch {
+		case errors.Is(o.err, repo.ErrDirty), errors.Is(o.err, repo.ErrDetached), errors.Is(o.err, repo.ErrNoUpstream):
+			skipped++
+			log.Print("{}  skipped: {}", name, o.err)
+		case errors.Is(o.err, repo.ErrDiverged):
+			diverged++
+			log.Print("{}  {}", name, o.err)
+		case o.err != nil:
+			failed++
+			log.Print("{}  failed: {}", name, o.err)
+		case o.result.From == o.result.To:
+			upToDate++
+			log.Print("{}  up to date", name)
+		default:
+			advanced++
+			log.Print("{}  {}", name, describePull(o.result))
+		}
+	}
+
+	log.Print("")
+	log.Print("{} repositories: {} advanced, {} up to date, {} diverged, {} skipped, {} failed", len(outcomes), advanced, upToDate, diverged, skipped, failed)
+	if failed > 0 {
+		exitCode = 1
+	}
+}
+
+// describePull says how a branch moved, e.g. "advanced 3 commits
+// (1a2b3c4..5d6e7f8)".
+func describePull(result repo.PullResult) string {
+	verb := "advanced"
+	if result.Rebased {
+		verb = "rebased onto"
+	}
+	noun := "commits"
+	if result.Commits == 1 {
+		noun = "commit"
+	}
+	return fmt.Sprintf("%s %d %s (%s..%s)", verb, result.Commits, noun, shortHash(result.From), shortHash(result.To))
+}
+
+func shortHash(commit string) string {
+	return commit[:min(7, len(commit))]
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/path"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/search"
+	"github.com/spf13/cobra"
+)
+
+// selection picks the repositories a command runs over. Commands register its
+// flags with addSelectionFlags and call repos to resolve them.
+type selection struct {
+	group     string
+	query     string
+	selector  string
+	pinned    bool
+	stdin     bool
+	worktrees bool
+}
+
+func addSelectionFlags(cmd *cobra.Command, s *selection) {
+	cmd.Flags().StringVarP(&s.group, "group", "g", "", "Only repositories in this search group, by path or index")
+	cmd.Flags().StringVarP(&s.query, "query", "q", "", "Only repositories matching this query")
+	cmd.Flags().StringVarP(&s.selector, "select", "s", "", selectUsage)
+	cmd.Flags().BoolVarP(&s.pinned, "pinned", "p", false, "Only pinned repositories")
+	cmd.Flags().BoolVar(&s.stdin, "stdin", false, "Read repository paths from stdin, one per line, e.g. from 'gimme list --paths'")
+	cmd.Flags().BoolVar(&s.worktrees, "worktrees", false, "Include linked worktrees")
+}
+
+// selectUsage describes the --select flag of every command that has one.
+const selectUsage = `Only repositories matching this expression: tag:<tag>, group:<group>, pinned, dirty or an identifier glob, ! to negate, commas for "or" and spaces for "and"`
+
+// repos returns the selected repositories, pinned ones first and the rest by
+// name. With --stdin they're read from stdin, otherwise discovered in the
+// search groups; either way the other flags filter them.
+func (s *selection) repos(stdin io.Reader) ([]repo.Repo, error) {
+	folders := config.GetSearchFolders()
+	if s.group != "" {
+		group, err := search.ResolveGroup(s.group)
+		if err != nil {
+			return nil, err
+		}
+		folders = []string{group}
+	}
+	selector, err := search.ParseSelector(s.selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []repo.Repo
+	if s.stdin {
+		if repos, err = readRepos(stdin); err != nil {
+			return nil, err
+		}
+	} else {
+		repos = search.Repositories(search.RepoSearchOptions{SearchFolders: folders})
+	}
+
+	repos = slices.DeleteFunc(repos, func(r repo.Repo) bool {
+		return (s.pinned && !r.Pinned) ||
+			(!s.worktrees && r.IsWorktree()) ||
+			(s.query != "" && search.MatchRepo(s.query, r.Name, r.Identifier, r.Path) == 0) ||
+			(s.group != "" && !path.IsWithin(r.Path, folders[0]))
+	})
+	repos = selector.Filter(repos, jobs(0))
+	search.SortByPins(repos)
+	return repos, nil
+}
+
+// parseSelect parses a --select flag, logging why when it's invalid.
+func parseSelect(expr string) (*search.Selector, bool) {
+	selector, err := search.ParseSelector(expr)
+	if err != nil {
+		log.Error("Invalid --select: {}", err)
+		return nil, false
+	}
+	return selector, true
+}
+
+// readRepos reads repository paths, one per line, and opens the repository
+// containing each. Duplicates are dropped.
+func readRepos(r io.Reader) ([]repo.Repo, error) {
+	repos := []repo.Repo{}
+	seen := map[string]bool{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		dir, _ := path.Normalize(line)
+		found := search.FindRepoForPath(dir)
+		if found == nil {
+			return nil, fmt.Errorf("%q is not in a git repository", line)
+		}
+		if !seen[found.Path] {
+			seen[found.Path] = true
+			repos = append(repos, *found)
+		}
+	}
+	return repos, scanner.Err()
+}
+
+// jobs returns how many repositories to work on at once: the --jobs flag if
+// set, otherwise search.parallelism.
+func jobs(flag int) int {
+	if flag > 0 {
+		return flag
+	}
+	return config.GetSearchParallelism()
+}
@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/parallel"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/search"
+	"github.com/kernelle-soft/gimme/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	snapshotSelection selection
+	snapshotForceFlag bool
+)
+
+var snapshotCommand = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Record and restore the commit every repository is at",
+	Long: `Record the branch and commit every repository is at, and check them out again later, e.g. to reproduce a bug across services.
+
+Snapshots are kept in $XDG_STATE_HOME/gimme/snapshots (default ~/.local/state/gimme/snapshots). A name containing a slash or ending in .json is a file instead, so a snapshot can be shared as a lockfile.
+
+  gimme snapshot save <name>      - record every repository, or those selected
+  gimme snapshot restore <name>   - check out what a snapshot recorded
+  gimme snapshot diff <a> [b]     - compare two snapshots, or one with now
+  gimme snapshot ls               - list snapshots
+  gimme snapshot delete <name>    - delete a snapshot`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var snapshotSaveCommand = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Record the commit every repository is at",
+	Long: `Record the branch and commit of every repository, or of those selected. Uncommitted changes aren't recorded; gimme warns about repositories that have them.
+
+Example:
+	gimme snapshot save bug-123
+	gimme snapshot save -g ~/work ./bug-123.json   # a lockfile to attach to the bug`,
+	Args: cobra.ExactArgs(1),
+	Run:  snapshotSaveRun,
+}
+
+var snapshotRestoreCommand = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Check out the commits a snapshot recorded",
+	Long: `Check out the commit each repository in a snapshot was at: on its branch if the branch is still there, detached otherwise. Commits the repository doesn't have are fetched.
+
+Nothing is checked out if any of the repositories has uncommitted changes or an operation in progress. Repositories that can't be found are reported and skipped.`,
+	Args:              cobra.ExactArgs(1),
+	Run:               snapshotRestoreRun,
+	ValidArgsFunction: completeSnapshots,
+}
+
+var snapshotDiffCommand = &cobra.Command{
+	Use:               "diff <a> [b]",
+	Short:             "Compare two snapshots, or a snapshot with now",
+	Long:              `List the repositories whose branch or commit differ between two snapshots. Without b, the snapshot is compared with where its repositories are now.`,
+	Args:              cobra.RangeArgs(1, 2),
+	Run:               snapshotDiffRun,
+	ValidArgsFunction: completeSnapshots,
+}
+
+var snapshotLsCommand = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List snapshots",
+	Args:    cobra.NoArgs,
+	Run:     snapshotLsRun,
+}
+
+var snapshotDeleteCommand = &cobra.Command{
+	Use:               "delete <name>",
+	Aliases:           []string{"rm"},
+	Short:             "Delete a snapshot",
+	Args:              cobra.ExactArgs(1),
+	Run:               snapshotDeleteRun,
+	ValidArgsFunction: completeSnapshots,
+}
+
+func init() {
+	addSelectionFlags(snapshotSaveCommand, &snapshotSelection)
+	snapshotSaveCommand.Flags().BoolVarP(&snapshotForceFlag, "force", "f", false, "Replace a snapshot of the same name")
+
+	snapshotCommand.AddCommand(snapshotSaveCommand)
+	snapshotCommand.AddCommand(snapshotRestoreCommand)
+	snapshotCommand.AddCommand(snapshotDiffCommand)
+	snapshotCommand.AddCommand(snapshotLsCommand)
+	snapshotCommand.AddCommand(snapshotDeleteCommand)
+}
+
+var snapshotSaveRun = func(cmd *cobra.Command, args []string) {
+	repos, err := snapshotSelection.repos(cmd.InOrStdin())
+	if err != nil {
+		log.Error("Could not select repositories: {}", err)
+		exitCode = 1
+		return
+	}
+
+	s := takeSnapshot(repos, true)
+	if len(s.Repositories) == 0 {
+		log.Print("No repositories to record.")
+		return
+	}
+
+	err = s.Save(args[0], snapshotForceFlag)
+	if errors.Is(err, snapshot.ErrExists) {
+		log.Print("Snapshot \"{}\" already exists; pass --force to replace it.", args[0])
+		exitCode = 1
+		return
+	}
+	if err != nil {
+		log.Error("Could not save snapshot: {}", err)
+		exitCode = 1
+		return
+	}
+	log.Print("Recorded {} in snapshot \"{}\".", countRepositories(len(s.Repositories)), args[0])
+}
+
+// takeSnapshot records where repos are now. Repositories without commits are
+// left out; with warn, so are ones whose status can't be read, and ones with
+// uncommitted changes are pointed out.
+func takeSnapshot(repos []repo.Repo, warn bool) *snapshot.Snapshot {
+	results := parallel.Map(repos, jobs(0), func(r repo.Repo) repoStatus {
+		status, err := r.Status()
+		return repoStatus{repo: r, status: status, err: err}
+	})
+
+	s := snapshot.New()
+	for _, result := range results {
+		r, status := result.repo, result.status
+		switch {
+		case result.err != nil:
+			if warn {
+				log.Warning("Skipping \"{}\": {}", r.Path, result.err)
+			}
+			continue
+		case status.Head == "":
+			continue
+		case warn && (status.IsDirty() || status.Operation != ""):
+			log.Warning("\"{}\" has uncommitted changes, which the snapshot won't include.", r.Path)
+		}
+
+		if existing, ok := s.Repositories[r.Identifier]; ok {
+			if warn {
+				log.Warning("\"{}\" and \"{}\" are both {}; recording the first.", existing.Path, r.Path, r.Identifier)
+			}
+			continue
+		}
+		s.Repositories[r.Identifier] = snapshot.Repo{Path: r.Path, Branch: status.Branch, Head: status.Head}
+	}
+	return s
+}
+
+// snapshotTarget is a repository in a snapshot and where it is now.
+type snapshotTarget struct {
+	identifier string
+	recorded   snapshot.Repo
+	repo       repo.Repo
+	status     repo.Status
+	err        error // why status couldn't be read
+}
+
+var snapshotRestoreRun = func(cmd *cobra.Command, args []string) {
+	s, ok := loadSnapshot(args[0])
+	if !ok {
+		return
+	}
+
+	targets, missing := findSnapshotRepos(s)
+	for _, id := range missing {
+		log.Warning("{} isn't in any search group; skipping it.", id)
+	}
+
+	targets = parallel.Map(targets, jobs(0), func(t snapshotTarget) snapshotTarget {
+		t.status, t.err = t.repo.Status()
+		return t
+	})
+	// A repository whose status can't be read might have changes too
+	dirty := []snapshotTarget{}
+	for _, t := range targets {
+		if t.err != nil || t.status.IsDirty() || t.status.Operation != "" {
+			dirty = append(dirty, t)
+		}
+	}
+	if len(dirty) > 0 {
+		log.Error("Not restoring, because of uncommitted changes, an operation in progress or an unreadable status in {}:", countRepositories(len(dirty)))
+		for _, t := range dirty {
+			if t.err != nil {
+				log.Print("  {} ({}): could not read status: {}", t.identifier, t.repo.Path, t.err)
+			} else {
+				log.Print("  {} ({}): {}", t.identifier, t.repo.Path, describeStatus(t.status))
+			}
+		}
+		exitCode = 1
+		return
+	}
+
+	width := 0
+	for _, t := range targets {
+		width = max(width, len(t.identifier))
+	}
+
+	errs := parallel.Map(targets, jobs(0), func(t snapshotTarget) error {
+		if t.status.Head == t.recorded.Head && t.status.Branch == t.recorded.Branch {
+			return nil
+		}
+		_, err := t.repo.CheckoutCommit(t.recorded.Branch, t.recorded.Head)
+		return err
+	})
+
+	var restored, unchanged, failed int
+	for i, t := range targets {
+		name := pad(t.identifier, width)
+		switch {
+		case errs[i] != nil:
+			failed++
+			log.Print("{}  failed: {}", name, errs[i])
+		case t.status.Head == t.recorded.Head && t.status.Branch == t.recorded.Branch:
+			unchanged++
+			log.Print("{}  already at {}", name, describeSnapshotRepo(&t.recorded))
+		default:
+			restored++
+			if now, err := t.repo.Status(); err == nil && now.Branch == "" && t.recorded.Branch != "" {
+				log.Print("{}  detached at {} ({} has moved on)", name, shortHash(t.recorded.Head), t.recorded.Branch)
+			} else {
+				log.Print("{}  checked out {}", name, describeSnapshotRepo(&t.recorded))
+			}
+		}
+	}
+
+	log.Print("")
+	log.Print("{} restored, {} already there, {} missing, {} failed", restored, unchanged, len(missing), failed)
+	if failed > 0 {
+		exitCode = 1
+	}
+}
+
+// findSnapshotRepos finds the repositories a snapshot recorded, by identifier
+// in the search groups, or where they were if they're still there. Returns the
+// identifiers of the ones it can't find too.
+func findSnapshotRepos(s *snapshot.Snapshot) ([]snapshotTarget, []string) {
+	byIdentifier := map[string]repo.Repo{}
+	for _, r := range search.Repositories(search.DefaultRepoSearchOptions()) {
+		if _, ok := byIdentifier[r.Identifier]; !ok && !r.IsWorktree() {
+			byIdentifier[r.Identifier] = r
+		}
+	}
+
+	targets, missing := []snapshotTarget{}, []string{}
+	for _, id := range slices.Sorted(maps.Keys(s.Repositories)) {
+		recorded := s.Repositories[id]
+		r, ok := byIdentifier[id]
+		if !ok {
+			if found := search.FindRepoForPath(recorded.Path); found != nil && found.Path == recorded.Path && found.Identifier == id {
+				r, ok = *found, true
+			}
+		}
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		targets = append(targets, snapshotTarget{identifier: id, recorded: recorded, repo: r})
+	}
+	return targets, missing
+}
+
+var snapshotDiffRun = func(cmd *cobra.Command, args []string) {
+	before, ok := loadSnapshot(args[0])
+	if !ok {
+		return
+	}
+
+	var after *snapshot.Snapshot
+	if len(args) > 1 {
+		if after, ok = loadSnapshot(args[1]); !ok {
+			return
+		}
+	} else {
+		targets, _ := findSnapshotRepos(before)
+		repos := make([]repo.Repo, len(targets))
+		for i, t := range targets {
+			repos[i] = t.repo
+		}
+		after = takeSnapshot(repos, false)
+	}
+
+	changes := snapshot.Diff(before, after)
+	if len(changes) == 0 {
+		log.Print("No differences.")
+		return
+	}
+
+	width := 0
+	for _, c := range changes {
+		width = max(width, len(c.Identifier))
+	}
+	for _, c := range changes {
+		log.Print("{}  {} -> {}", pad(c.Identifier, width), describeSnapshotRepo(c.Before), describeSnapshotRepo(c.After))
+	}
+}
+
+var snapshotLsRun = func(cmd *cobra.Command, args []string) {
+	names, err := snapshot.List()
+	if err != nil {
+		log.Error("Could not list snapshots: {}", err)
+		exitCode = 1
+		return
+	}
+	if len(names) == 0 {
+		log.Print("No snapshots saved.")
+		return
+	}
+
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	for _, name := range names {
+		s, err := snapshot.Load(name)
+		if err != nil {
+			log.Print("{}  could not be read: {}", pad(name, width), err)
+			continue
+		}
+		log.Print("{}  {}  {}", pad(name, width), s.Created.Format(time.DateTime), countRepositories(len(s.Repositories)))
+	}
+}
+
+var snapshotDeleteRun = func(cmd *cobra.Command, args []string) {
+	err := snapshot.Delete(args[0])
+	if errors.Is(err, snapshot.ErrNotFound) {
+		log.Print("No snapshot named \"{}\".", args[0])
+		exitCode = 1
+		return
+	}
+	if err != nil {
+		log.Error("Could not delete snapshot: {}", err)
+		exitCode = 1
+		return
+	}
+	log.Print("Deleted snapshot \"{}\".", args[0])
+}
+
+// loadSnapshot loads the snapshot called name, logging why and failing the
+// command when it can't.
+func loadSnapshot(name string) (*snapshot.Snapshot, bool) {
+	s, err := snapshot.Load(name)
+	if errors.Is(err, snapshot.ErrNotFound) {
+		log.Print("No snapshot named \"{}\". See 'gimme snapshot ls'.", name)
+		exitCode = 1
+		return nil, false
+	}
+	if err != nil {
+		log.Error("Could not read snapshot \"{}\": {}", name, err)
+		exitCode = 1
+		return nil, false
+	}
+	return s, true
+}
+
+// countRepositories returns e.g. "1 repository" or "3 repositories".
+func countRepositories(n int) string {
+	if n == 1 {
+		return "1 repository"
+	}
+	return fmt.Sprintf("%d repositories", n)
+}
+
+// describeSnapshotRepo says where a snapshot had a repository, e.g.
+// "main at 1a2b3c4", or "(none)" if it didn't have it.
+func describeSnapshotRepo(r *snapshot.Repo) string {
+	switch {
+	case r == nil:
+		return "(none)"
+	case r.Branch == "":
+		return "detached at " + shortHash(r.Head)
+	default:
+		return r.Branch + " at " + shortHash(r.Head)
+	}
+}
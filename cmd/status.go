@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/parallel"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusDirtyFlag  bool
+	statusAheadFlag  bool
+	statusBehindFlag bool
+	statusJobsFlag   int
+	statusSelectFlag string
+)
+
+var statusCommand = &cobra.Command{
+	Use:   "status [query]",
+	Short: "Show the state of every repository",
+	Long: `Show the state of every repository, or of those matching query: the branch checked out, commits ahead of and behind its upstream, staged, modified, untracked and conflicted files, stashes, and any rebase or merge left in progress. A summary line follows.
+
+Example:
+	gimme status          # every repository
+	gimme status --dirty  # only repositories with uncommitted changes
+	gimme status --ahead  # only repositories with commits to push`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  statusRun,
+}
+
+func init() {
+	statusCommand.Flags().BoolVar(&statusDirtyFlag, "dirty", false, "Show only repositories with uncommitted changes")
+	statusCommand.Flags().BoolVar(&statusAheadFlag, "ahead", false, "Show only repositories with commits their upstream doesn't have")
+	statusCommand.Flags().BoolVar(&statusBehindFlag, "behind", false, "Show only repositories missing commits from their upstream")
+	statusCommand.Flags().StringVarP(&statusSelectFlag, "select", "s", "", selectUsage)
+	statusCommand.Flags().IntVarP(&statusJobsFlag, "jobs", "j", 0, "How many repositories to check at once (default search.parallelism)")
+}
+
+// repoStatus is one repository's status, or the error reading it.
+type repoStatus struct {
+	repo   repo.Repo
+	status repo.Status
+	err    error
+}
+
+var statusRun = func(cmd *cobra.Command, args []string) {
+	var query string
+	if len(args) > 0 {
+		query = args[0]
+	}
+
+	selector, ok := parseSelect(statusSelectFlag)
+	if !ok {
+		return
+	}
+
+	repos := selector.Filter(search.Repositories(search.ForRepo(query)), jobs(statusJobsFlag))
+	if len(repos) == 0 {
+		log.Print("No repositories found.")
+		return
+	}
+
+	results := parallel.Map(repos, jobs(statusJobsFlag), func(r repo.Repo) repoStatus {
+		status, err := r.Status()
+		return repoStatus{repo: r, status: status, err: err}
+	})
+
+	shown := []repoStatus{}
+	nameWidth, branchWidth := 0, 0
+	for _, result := range results {
+		if !statusMatchesFilters(result) {
+			continue
+		}
+		shown = append(shown, result)
+		nameWidth = max(nameWidth, len(result.repo.Name))
+		branchWidth = max(branchWidth, len(statusBranch(result.status)))
+	}
+
+	for _, result := range shown {
+		if result.err != nil {
+			log.Print("{}  could not read status: {}", pad(result.repo.Name, nameWidth), result.err)
+			continue
+		}
+		log.Print("{}  {}  {}", pad(result.repo.Name, nameWidth), pad(statusBranch(result.status), branchWidth), describeStatus(result.status))
+	}
+
+	if len(shown) > 0 {
+		log.Print("")
+	}
+	log.Print("{}", statusSummary(results))
+}
+
+// statusMatchesFilters reports whether a result passes --dirty, --ahead and
+// --behind. Repositories whose status couldn't be read are always shown.
+func statusMatchesFilters(result repoStatus) bool {
+	if result.err != nil {
+		return true
+	}
+	s := result.status
+	return (!statusDirtyFlag || s.IsDirty()) &&
+		(!statusAheadFlag || s.Ahead > 0) &&
+		(!statusBehindFlag || s.Behind > 0)
+}
+
+// statusBranch names what's checked out: the branch, a detached commit, or
+// nothing yet.
+func statusBranch(s repo.Status) string {
+	switch {
+	case s.Branch != "":
+		return s.Branch
+	case s.Head == "":
+		return "(no commits)"
+	default:
+		return "(detached " + shortHash(s.Head) + ")"
+	}
+}
+
+// describeStatus lists what's noteworthy about a working tree, e.g.
+// "rebase in progress, 2 ahead, 3 modified", or "clean".
+func describeStatus(s repo.Status) string {
+	parts := []string{}
+	if s.Operation != "" {
+		parts = append(parts, s.Operation+" in progress")
+	}
+	if s.Branch != "" && s.Upstream == "" {
+		parts = append(parts, "no upstream")
+	}
+	for _, count := range []struct {
+		n    int
+		what string
+	}{
+		{s.Ahead, "ahead"},
+		{s.Behind, "behind"},
+		{s.Conflicted, "conflicted"},
+		{s.Staged, "staged"},
+		{s.Unstaged, "modified"},
+		{s.Untracked, "untracked"},
+	} {
+		if count.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count.n, count.what))
+		}
+	}
+	if s.Stashes == 1 {
+		parts = append(parts, "1 stash")
+	} else if s.Stashes > 1 {
+		parts = append(parts, fmt.Sprintf("%d stashes", s.Stashes))
+	}
+
+	if len(parts) == 0 {
+		return "clean"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// statusSummary counts the repositories in each state, e.g.
+// "12 repositories: 3 dirty, 1 ahead, 2 behind, 1 in progress".
+func statusSummary(results []repoStatus) string {
+	var dirty, ahead, behind, inProgress, failed int
+	for _, result := range results {
+		if result.err != nil {
+			failed++
+			continue
+		}
+		s := result.status
+		if s.IsDirty() {
+			dirty++
+		}
+		if s.Ahead > 0 {
+			ahead++
+		}
+		if s.Behind > 0 {
+			behind++
+		}
+		if s.Operation != "" {
+			inProgress++
+		}
+	}
+
+	noun := "repositories"
+	if len(results) == 1 {
+		noun = "repository"
+	}
+	summary := fmt.Sprintf("%d %s: %d dirty, %d ahead, %d behind, %d in progress", len(results), noun, dirty, ahead, behind, inProgress)
+	if failed > 0 {
+		summary += fmt.Sprintf(", %d unreadable", failed)
+	}
+	return summary
+}
+
+// pad right-pads s with spaces to width.
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-len(s), 0))
+}
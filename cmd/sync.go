@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/parallel"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncSelection selection
+	syncFetchFlag bool
+	syncJobsFlag  int
+)
+
+var syncCommand = &cobra.Command{
+	Use:   "sync",
+	Short: "Fast-forward pinned branches to their upstream",
+	Long: `Fast-forward the globally pinned branches, main and master by default (see 'gimme config ls branch'), of every selected repository to their upstream, without checking them out. This keeps the branches 'gimme clean -b' checks merges against current.
+
+A pinned branch that's checked out is fast-forwarded in its worktree, unless that has local changes. Branches with commits of their own are left alone. Run 'gimme fetch' first, or pass --fetch, to sync with what's on the remote now.
+
+Example:
+	gimme sync --fetch       # fetch, then update main/master everywhere`,
+	Args: cobra.NoArgs,
+	Run:  syncRun,
+}
+
+func init() {
+	addSelectionFlags(syncCommand, &syncSelection)
+	syncCommand.Flags().BoolVar(&syncFetchFlag, "fetch", false, "Fetch each repository first")
+	syncCommand.Flags().IntVarP(&syncJobsFlag, "jobs", "j", 0, "How many repositories to sync at once (default search.parallelism)")
+}
+
+// branchOutcome is how fast-forwarding one branch went.
+type branchOutcome struct {
+	branch string
+	result repo.PullResult
+	err    error
+}
+
+// syncOutcome is how syncing one repository went. err is set when fetching
+// failed and no branch was tried.
+type syncOutcome struct {
+	repo     repo.Repo
+	branches []branchOutcome
+	err      error
+}
+
+var syncRun = func(cmd *cobra.Command, args []string) {
+	repos, err := syncSelection.repos(cmd.InOrStdin())
+	if err != nil {
+		log.Error("Could not select repositories: {}", err)
+		exitCode = 1
+		return
+	}
+	if len(repos) == 0 {
+		log.Print("No repositories selected.")
+		return
+	}
+
+	protected := config.GetGlobalPinnedBranches()
+	outcomes := parallel.Map(repos, jobs(syncJobsFlag), func(r repo.Repo) syncOutcome {
+		if syncFetchFlag {
+			if _, err := r.Fetch(false); err != nil {
+				return syncOutcome{repo: r, err: err}
+			}
+		}
+
+		outcome := syncOutcome{repo: r}
+		for _, branch := range protected {
+			if !r.BranchExists(branch) {
+				continue
+			}
+			result, err := r.FastForward(branch)
+			outcome.branches = append(outcome.branches, branchOutcome{branch: branch, result: result, err: err})
+		}
+		return outcome
+	})
+
+	width := 0
+	for _, o := range outcomes {
+		if o.err != nil || len(o.branches) > 0 {
+			width = max(width, len(o.repo.Name))
+		}
+	}
+
+	var advanced, upToDate, diverged, skipped, failed int
+	for _, o := range outcomes {
+		name := pad(o.repo.Name, width)
+		if o.err != nil {
+			failed++
+			log.Print("{}  fetch failed: {}", name, o.err)
+			continue
+		}
+
+		for _, b := range o.branches {
+			switch {
+			case errors.Is(b.err, repo.ErrNoUpstream), errors.Is(b.err, repo.ErrDirty):
+				skipped++
+				log.Print("{}  {}: skipped, {}", name, b.branch, b.err)
+			case errors.Is(b.err, repo.ErrDiverged):
+				diverged++
+				log.Print("{}  {}: {}", name, b.branch, b.err)
+			case b.err != nil:
+				failed++
+				log.Print("{}  {}: failed: {}", name, b.branch, b.err)
+			case b.result.From == b.result.To:
+				upToDate++
+				log.Print("{}  {}: up to date", name, b.branch)
+			default:
+				advanced++
+				log.Print("{}  {}: {}", name, b.branch, describePull(b.result))
+			}
+		}
+	}
+
+	total := advanced + upToDate + diverged + skipped + failed
+	if total == 0 {
+		log.Print("None of the selected repositories have a pinned branch.")
+		return
+	}
+
+	log.Print("")
+	noun := "branches"
+	if total == 1 {
+		noun = "branch"
+	}
+	log.Print("{} {}: {} advanced, {} up to date, {} diverged, {} skipped, {} failed", total, noun, advanced, upToDate, diverged, skipped, failed)
+	if failed > 0 {
+		exitCode = 1
+	}
+}
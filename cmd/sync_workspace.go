@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/manifest"
+	"github.com/kernelle-soft/gimme/internal/parallel"
+	"github.com/kernelle-soft/gimme/internal/path"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncWorkspaceDryRunFlag bool
+	syncWorkspaceJobsFlag   int
+)
+
+var syncWorkspaceCommand = &cobra.Command{
+	Use:   "sync-workspace [manifest]",
+	Short: "Clone and set up the repositories a workspace manifest lists",
+	Long: `Bring the workspace in line with a manifest: add its search groups, clone the repositories that are missing, and apply the pins, aliases and protected branches it declares. Repositories in the manifest's groups that it doesn't list are reported, never removed. Running it again only does what's left to do.
+
+The manifest is YAML, read from ` + manifest.DefaultPath + ` unless another file is given:
+
+	groups: [~/work]              # search groups to add
+	protected: [develop]          # branches protected in every repository
+	repositories:
+	  - url: git@github.com:acme/api.git
+	    group: ~/work             # clone into this group by clone.layout (default: clone.root)
+	    path: ~/work/api          # or clone exactly here
+	    pinned: true
+	    aliases: [api]
+	    protected: [release]      # branches protected in this repository
+
+Relative paths are relative to the manifest, so a team can check one into a shared repository. A repository's group and path must be inside clone.root or a search group you already have, so a shared manifest can't clone anywhere else on disk.
+
+Example:
+	gimme sync-workspace                              # ` + manifest.DefaultPath + `
+	gimme sync-workspace ~/src/team/workspace.yaml --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  syncWorkspaceRun,
+}
+
+func init() {
+	syncWorkspaceCommand.Flags().BoolVarP(&syncWorkspaceDryRunFlag, "dry-run", "n", false, "Show what would change without changing anything")
+	syncWorkspaceCommand.Flags().IntVarP(&syncWorkspaceJobsFlag, "jobs", "j", 0, "How many repositories to clone at once (default search.parallelism)")
+}
+
+// workspaceRepo is what became of one repository in the manifest.
+type workspaceRepo struct {
+	entry   manifest.Repository
+	dest    string
+	present bool // already in the workspace, so not cloned
+	err     error
+}
+
+// name is how the repository is shown: its identifier, or its URL if it has
+// none.
+func (w workspaceRepo) name() string {
+	if id := w.entry.Identifier(); id != "" {
+		return id
+	}
+	return w.entry.URL
+}
+
+// identifier is what the repository's settings are keyed by. Without one in
+// its URL, it's worked out from the clone like any repository's.
+func (w workspaceRepo) identifier() string {
+	if id := w.entry.Identifier(); id != "" {
+		return id
+	}
+	return repo.IdentifierFromPath(w.dest)
+}
+
+var syncWorkspaceRun = func(cmd *cobra.Command, args []string) {
+	file := manifest.DefaultPath
+	if len(args) > 0 {
+		file = args[0]
+	}
+	m, err := manifest.Load(file)
+	if err != nil {
+		log.Error("Could not read manifest: {}", err)
+		exitCode = 1
+		return
+	}
+
+	root, err := config.GetCloneRoot()
+	if err != nil {
+		log.Error("Could not work out where to clone: {}", err)
+		exitCode = 1
+		return
+	}
+	if err := m.Confine(append(config.GetSearchFolders(), root)); err != nil {
+		log.Error("Refusing to clone outside the workspace: {}", err)
+		exitCode = 1
+		return
+	}
+
+	// Groups first, so repositories already cloned into them are found
+	groups := workspaceGroups(m)
+	for _, group := range groups {
+		if !slices.Contains(config.GetSearchFolders(), group) {
+			workspaceChange("add search group \""+group+"\"", func() error {
+				if err := os.MkdirAll(group, 0o755); err != nil {
+					return err
+				}
+				return config.AddGroup(group)
+			})
+		}
+	}
+
+	existing := map[string]repo.Repo{}
+	for _, r := range search.Repositories(search.DefaultRepoSearchOptions()) {
+		if !r.IsWorktree() {
+			existing[r.Identifier] = r
+		}
+	}
+
+	layout := config.GetCloneLayout()
+	results := parallel.Map(m.Repositories, jobs(syncWorkspaceJobsFlag), func(entry manifest.Repository) workspaceRepo {
+		return cloneWorkspaceRepo(entry, existing, root, layout)
+	})
+
+	width := 0
+	for _, result := range results {
+		width = max(width, len(result.name()))
+	}
+
+	var cloned, present, failed int
+	for _, result := range results {
+		name := pad(result.name(), width)
+		switch {
+		case result.err != nil:
+			failed++
+			log.Print("{}  failed: {}", name, result.err)
+		case result.present:
+			present++
+			log.Print("{}  present at \"{}\"", name, result.dest)
+		case syncWorkspaceDryRunFlag:
+			cloned++
+			log.Print("{}  would clone to \"{}\"", name, result.dest)
+		default:
+			cloned++
+			log.Print("{}  cloned to \"{}\"", name, result.dest)
+		}
+	}
+
+	applyWorkspaceSettings(m, results)
+	dirs := groups
+	if slices.ContainsFunc(m.Repositories, func(r manifest.Repository) bool { return r.Group == "" && r.Path == "" }) {
+		dirs = append(dirs, root)
+	}
+	extras := workspaceExtras(existing, results, dirs)
+	if len(extras) > 0 {
+		log.Print("")
+		log.Print("Not in the manifest:")
+		for _, r := range extras {
+			log.Print("  {} ({})", r.Name, r.Path)
+		}
+	}
+
+	log.Print("")
+	verb := "cloned"
+	if syncWorkspaceDryRunFlag {
+		verb = "to clone"
+	}
+	log.Print("{} repositories: {} {}, {} already present, {} failed, {} not in the manifest", len(results), cloned, verb, present, failed, len(extras))
+	if failed > 0 {
+		exitCode = 1
+	}
+}
+
+// cloneWorkspaceRepo clones entry unless it's already in the workspace, either
+// found in the search groups by identifier or sitting where it'd be cloned.
+func cloneWorkspaceRepo(entry manifest.Repository, existing map[string]repo.Repo, root, layout string) workspaceRepo {
+	result := workspaceRepo{entry: entry}
+	if r, ok := existing[entry.Identifier()]; ok {
+		result.dest, result.present = r.Path, true
+		return result
+	}
+
+	if result.dest, result.err = entry.Destination(root, layout); result.err != nil {
+		return result
+	}
+	if found := search.FindRepoForPath(result.dest); found != nil && found.Path == result.dest {
+		if id := entry.Identifier(); id != "" && found.Identifier != id {
+			result.err = fmt.Errorf("%q already holds another repository (%s)", result.dest, found.Identifier)
+		}
+		result.present = result.err == nil
+		return result
+	}
+
+	if !syncWorkspaceDryRunFlag {
+		var cloned repo.Repo
+		if cloned, result.err = repo.Clone(entry.URL, result.dest); result.err == nil {
+			runPostClone(cloned)
+		}
+	}
+	return result
+}
+
+// applyWorkspaceSettings applies the protected branches, pins and aliases the
+// manifest declares, skipping those already in the config. A repository that
+// couldn't be cloned still gets its aliases and protected branches, but can't
+// be pinned, since pins are by path.
+func applyWorkspaceSettings(m *manifest.Manifest, results []workspaceRepo) {
+	for _, branch := range m.Protected {
+		if !config.IsBranchGloballyPinned(branch) {
+			workspaceChange("protect branch \""+branch+"\" everywhere", func() error { return config.AddGlobalPinnedBranch(branch) })
+		}
+	}
+
+	for _, result := range results {
+		identifier := result.identifier()
+		if result.entry.Pinned && result.err == nil && !slices.Contains(config.GetPinnedRepos(), result.dest) {
+			workspaceChange("pin \""+result.dest+"\"", func() error { return config.AddPinnedRepo(result.dest) })
+		}
+		for _, alias := range result.entry.Aliases {
+			if config.GetAliases()[alias] != identifier {
+				workspaceChange("alias \""+alias+"\" to \""+identifier+"\"", func() error { return config.AddAlias(alias, identifier) })
+			}
+		}
+		for _, branch := range result.entry.Protected {
+			if !config.IsBranchPinnedForRepo(identifier, branch) {
+				workspaceChange("protect branch \""+branch+"\" in "+identifier, func() error { return config.AddRepoPinnedBranch(identifier, branch) })
+			}
+		}
+	}
+}
+
+// workspaceChange makes a change to the config, which logs it, or with
+// --dry-run only says what it would be.
+func workspaceChange(description string, apply func() error) {
+	if syncWorkspaceDryRunFlag {
+		log.Print("Would {}.", description)
+		return
+	}
+	if err := apply(); err != nil {
+		log.Error("Could not {}: {}", description, err)
+	}
+}
+
+// workspaceGroups returns the search groups the manifest declares or clones
+// into, without duplicates.
+func workspaceGroups(m *manifest.Manifest) []string {
+	groups := slices.Clone(m.Groups)
+	for _, r := range m.Repositories {
+		if r.Group != "" && !slices.Contains(groups, r.Group) {
+			groups = append(groups, r.Group)
+		}
+	}
+	return groups
+}
+
+// workspaceExtras returns the repositories inside dirs that the manifest
+// doesn't list, by name.
+func workspaceExtras(existing map[string]repo.Repo, results []workspaceRepo, dirs []string) []repo.Repo {
+	listed := map[string]bool{}
+	for _, result := range results {
+		listed[result.entry.Identifier()] = true
+		listed[result.dest] = true
+	}
+
+	extras := []repo.Repo{}
+	for _, r := range existing {
+		if listed[r.Identifier] || listed[r.Path] {
+			continue
+		}
+		if slices.ContainsFunc(dirs, func(dir string) bool { return path.IsWithin(r.Path, dir) }) {
+			extras = append(extras, r)
+		}
+	}
+	search.SortByPins(extras)
+	return extras
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+
+	"github.com/kernelle-soft/gimme/internal/directive"
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var taskCommand = &cobra.Command{
+	Use:   "task [name] [args...]",
+	Short: "Run a task from the current repository's .gimme.yaml",
+	Long: `Run a named task from the .gimme.yaml at the root of the current repository, or list them without a name. Tasks run in the repository root with sh; further arguments are passed on to them as "$@".
+
+Example .gimme.yaml:
+	tasks:
+	  test: go test ./...
+	  serve: docker compose up
+
+Example:
+	gimme task               # list the tasks
+	gimme task test -run Foo # go test ./... -run Foo`,
+	Run:               taskRun,
+	ValidArgsFunction: completeTasks,
+}
+
+func init() {
+	// Flags after the task name belong to the task
+	taskCommand.Flags().SetInterspersed(false)
+}
+
+var taskRun = func(cmd *cobra.Command, args []string) {
+	currentRepo, root, ok := currentWorktree()
+	if !ok {
+		return
+	}
+	settings := search.SettingsFor(currentRepo.Identifier, root)
+
+	if len(args) == 0 {
+		if len(settings.Tasks) == 0 {
+			log.Print("No tasks in \"{}\".", root+"/"+repo.LocalConfigFile)
+			return
+		}
+		names := slices.Sorted(maps.Keys(settings.Tasks))
+		width := 0
+		for _, name := range names {
+			width = max(width, len(name))
+		}
+		for _, name := range names {
+			log.Print("{}  {}", pad(name, width), settings.Tasks[name])
+		}
+		return
+	}
+
+	task, ok := settings.Tasks[args[0]]
+	if !ok {
+		log.Print("No task \"{}\". See 'gimme task'.", args[0])
+		exitCode = 1
+		return
+	}
+
+	if settings.Untrusted {
+		search.WarnUntrusted(root)
+	}
+
+	// The task's output goes to stderr, since stdout carries directives to
+	// the shell wrapper
+	c := exec.Command("sh", append([]string{"-c", task + ` "$@"`, args[0]}, args[1:]...)...)
+	c.Dir = root
+	c.Env = append(os.Environ(), directive.RepoEnv+"="+currentRepo.Identifier, directive.BranchEnv+"="+currentRepo.CurrentBranch())
+	for name, value := range settings.Env {
+		c.Env = append(c.Env, name+"="+value)
+	}
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+
+	err := c.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		exitCode = exitErr.ExitCode()
+	case err != nil:
+		log.Error("Could not run task \"{}\": {}", args[0], err)
+		exitCode = 1
+	}
+}
+
+// currentWorktree returns the repository containing the working directory
+// and the root of the working tree it's in, logging why when there isn't one.
+func currentWorktree() (*repo.Repo, string, bool) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		log.Error("Could not determine current directory: {}", err)
+		return nil, "", false
+	}
+
+	loc, err := repo.Locate(cwd)
+	currentRepo := search.FindRepoForPath(cwd)
+	if err != nil || currentRepo == nil {
+		log.Print("Not in a git repository.")
+		return nil, "", false
+	}
+	return currentRepo, loc.WorktreeRoot, true
+}
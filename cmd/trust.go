@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/trust"
+	"github.com/spf13/cobra"
+)
+
+var trustCommand = &cobra.Command{
+	Use:   "trust",
+	Short: "Trust the current repository's .gimme.yaml",
+	Long: `Trust the .gimme.yaml at the root of the current repository, so jumping to the repository runs its on-enter command and sets its env. Until then they are skipped, since anyone who can commit to the repository can change them.
+
+Trust covers the file as it is now: after it changes, check it and run 'gimme trust' again. 'gimme untrust' takes trust back.`,
+	Args: cobra.NoArgs,
+	Run:  trustRun,
+}
+
+var trustRun = func(cmd *cobra.Command, args []string) {
+	_, root, ok := currentWorktree()
+	if !ok {
+		return
+	}
+
+	local, err := repo.LoadLocalConfig(root)
+	if err != nil {
+		log.Error("Could not read \"{}\": {}", root, err)
+		exitCode = 1
+		return
+	}
+	path := filepath.Join(root, repo.LocalConfigFile)
+	if local.Hash == "" {
+		log.Print("No \"{}\" to trust.", path)
+		exitCode = 1
+		return
+	}
+
+	store, err := trust.Load()
+	if err != nil {
+		log.Error("Could not load trusted files: {}", err)
+		exitCode = 1
+		return
+	}
+	store.Trust(path, local.Hash)
+	if err := store.Save(); err != nil {
+		log.Error("Could not save trusted files: {}", err)
+		exitCode = 1
+		return
+	}
+	log.Print("Trusted \"{}\".", path)
+}
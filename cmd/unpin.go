@@ -25,7 +25,8 @@ With -b flag: unpins a branch in the current repo.
   gimme unpin -b <name> - unpin branch by name
 
 Note: Cannot unpin globally protected branches (main, master, etc.) - use config to modify those.`,
-	Run: unpinRun,
+	Run:               unpinRun,
+	ValidArgsFunction: completeUnpinArg,
 }
 
 func init() {
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/trust"
+	"github.com/spf13/cobra"
+)
+
+var untrustCommand = &cobra.Command{
+	Use:   "untrust",
+	Short: "Stop trusting the current repository's .gimme.yaml",
+	Long:  `Stop trusting the .gimme.yaml at the root of the current repository, so jumping to the repository skips its on-enter command and env again.`,
+	Args:  cobra.NoArgs,
+	Run:   untrustRun,
+}
+
+var untrustRun = func(cmd *cobra.Command, args []string) {
+	_, root, ok := currentWorktree()
+	if !ok {
+		return
+	}
+
+	store, err := trust.Load()
+	if err != nil {
+		log.Error("Could not load trusted files: {}", err)
+		exitCode = 1
+		return
+	}
+	path := filepath.Join(root, repo.LocalConfigFile)
+	if !store.Revoke(path) {
+		log.Print("\"{}\" is not trusted.", path)
+		return
+	}
+	if err := store.Save(); err != nil {
+		log.Error("Could not save trusted files: {}", err)
+		exitCode = 1
+		return
+	}
+	log.Print("Stopped trusting \"{}\".", path)
+}
@@ -0,0 +1,68 @@
+package worktree
+
+import (
+	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/path"
+	"github.com/kernelle-soft/gimme/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var (
+	addPathFlag string
+	addBaseFlag string
+)
+
+var addCommand = &cobra.Command{
+	Use:   "add <branch>",
+	Short: "Create a worktree for a branch",
+	Long: `Create a worktree for a branch and jump into it.
+
+If the branch exists locally or on origin it is checked out, otherwise it is created from --base (default: HEAD). If the branch is already checked out in a worktree, jumps there instead.`,
+	Args: cobra.ExactArgs(1),
+	Run:  addRun,
+}
+
+func init() {
+	addCommand.Flags().StringVar(&addPathFlag, "path", "", "Create the worktree at this path instead of the configured layout")
+	addCommand.Flags().StringVar(&addBaseFlag, "base", "", "Start point for a new branch (default: HEAD)")
+}
+
+var addRun = func(cmd *cobra.Command, args []string) {
+	currentRepo := currentRepo()
+	if currentRepo == nil {
+		return
+	}
+
+	branch := args[0]
+	if existing, ok := currentRepo.WorktreeForBranch(branch); ok {
+		log.Print("Branch \"{}\" is already checked out at \"{}\".", branch, existing.Path)
+		search.Land(existing.Path, existing.Path)
+		return
+	}
+
+	var wtPath string
+	var err error
+	if addPathFlag != "" {
+		wtPath, err = path.Normalize(addPathFlag)
+	} else {
+		wtPath, err = currentRepo.WorktreePath(branch, config.GetWorktreeLayout())
+	}
+	if err != nil {
+		log.Error("Could not determine worktree path: {}", err)
+		return
+	}
+
+	create := !currentRepo.BranchExists(branch) && !currentRepo.RemoteBranchExists(branch)
+	if err := currentRepo.AddWorktree(wtPath, branch, create, addBaseFlag); err != nil {
+		log.Error("Failed to create worktree: {}", err)
+		return
+	}
+
+	if create {
+		log.Print("Created branch \"{}\" in new worktree \"{}\".", branch, wtPath)
+	} else {
+		log.Print("Created worktree \"{}\" for branch \"{}\".", wtPath, branch)
+	}
+	search.Land(wtPath, wtPath)
+}
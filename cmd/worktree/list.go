@@ -0,0 +1,96 @@
+package worktree
+
+import (
+	"strings"
+
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var listAllFlag bool
+
+var listCommand = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List worktrees",
+	Long:    `List the current repository's worktrees with their branch and state. Use --all to list worktrees of every repository in the search groups.`,
+	Args:    cobra.NoArgs,
+	Run:     listRun,
+}
+
+func init() {
+	listCommand.Flags().BoolVarP(&listAllFlag, "all", "a", false, "List worktrees of every repository")
+}
+
+var listRun = func(cmd *cobra.Command, args []string) {
+	if !listAllFlag {
+		currentRepo := currentRepo()
+		if currentRepo == nil {
+			return
+		}
+		showWorktrees(currentRepo)
+		return
+	}
+
+	for _, r := range search.Repositories(search.DefaultRepoSearchOptions()) {
+		if r.IsWorktree() {
+			continue
+		}
+		showWorktrees(&r)
+	}
+}
+
+func showWorktrees(r *repo.Repo) {
+	worktrees, err := r.Worktrees()
+	if err != nil {
+		log.Error("Could not list worktrees for \"{}\": {}", r.Name, err)
+		return
+	}
+
+	current := currentWorktreeRoot()
+
+	log.Print("{}/", r.Name)
+	for _, wt := range worktrees {
+		prefix := "  "
+		if wt.Path == current {
+			prefix = "* "
+		}
+
+		branch := wt.Branch
+		if wt.Detached {
+			branch = "detached at " + shortHash(wt.Head)
+		}
+		if wt.Bare {
+			branch = "bare"
+		}
+
+		// Other status indicators in parentheses
+		statusIndicators := []string{}
+		if wt.Main {
+			statusIndicators = append(statusIndicators, "main")
+		}
+		if wt.Prunable {
+			statusIndicators = append(statusIndicators, "prunable")
+		} else if !wt.Bare && wt.IsDirty() {
+			statusIndicators = append(statusIndicators, "dirty")
+		}
+		if wt.Locked {
+			statusIndicators = append(statusIndicators, "locked")
+		}
+
+		statusPart := ""
+		if len(statusIndicators) > 0 {
+			statusPart = " (" + strings.Join(statusIndicators, ", ") + ")"
+		}
+		log.Print("{}{} [{}]{}", prefix, wt.Path, branch, statusPart)
+	}
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
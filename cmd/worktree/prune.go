@@ -0,0 +1,33 @@
+package worktree
+
+import (
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/spf13/cobra"
+)
+
+var pruneCommand = &cobra.Command{
+	Use:   "prune",
+	Short: "Prune stale worktrees",
+	Long:  `Clean up git's records of worktrees whose directories no longer exist.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		currentRepo := currentRepo()
+		if currentRepo == nil {
+			return
+		}
+
+		pruned, err := currentRepo.PruneWorktrees()
+		if err != nil {
+			log.Error("Failed to prune worktrees: {}", err)
+			return
+		}
+
+		if len(pruned) == 0 {
+			log.Print("Nothing to prune.")
+			return
+		}
+		for _, line := range pruned {
+			log.Print("{}", line)
+		}
+	},
+}
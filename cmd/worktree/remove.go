@@ -0,0 +1,85 @@
+package worktree
+
+import (
+	"path/filepath"
+
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/path"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var removeForceFlag bool
+
+var removeCommand = &cobra.Command{
+	Use:     "remove <branch|path>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a worktree",
+	Long:    `Remove a worktree by the branch it has checked out or by its path. Worktrees with local changes are kept unless --force is given. If you're inside the removed worktree, jumps back to the main repository.`,
+	Args:    cobra.ExactArgs(1),
+	Run:     removeRun,
+}
+
+func init() {
+	removeCommand.Flags().BoolVar(&removeForceFlag, "force", false, "Remove even if the worktree has local changes")
+}
+
+var removeRun = func(cmd *cobra.Command, args []string) {
+	currentRepo := currentRepo()
+	if currentRepo == nil {
+		return
+	}
+
+	worktrees, err := currentRepo.Worktrees()
+	if err != nil {
+		log.Error("Could not list worktrees: {}", err)
+		return
+	}
+
+	target, ok := findWorktree(worktrees, args[0])
+	if !ok {
+		log.Print("No worktree found for \"{}\".", args[0])
+		return
+	}
+	if target.Main {
+		log.Print("Cannot remove the main working tree.")
+		return
+	}
+
+	inside := currentWorktreeRoot() == target.Path
+	if err := currentRepo.RemoveWorktree(target.Path, removeForceFlag); err != nil {
+		log.Error("Failed to remove worktree: {}", err)
+		return
+	}
+	log.Print("Removed worktree \"{}\".", target.Path)
+
+	if inside {
+		search.Land(currentRepo.Path, currentRepo.Path)
+	}
+}
+
+// findWorktree finds a worktree by branch name, falling back to its path.
+func findWorktree(worktrees []repo.Worktree, branchOrPath string) (repo.Worktree, bool) {
+	for _, wt := range worktrees {
+		if wt.Branch == branchOrPath {
+			return wt, true
+		}
+	}
+
+	target, err := path.Normalize(branchOrPath)
+	if err != nil {
+		return repo.Worktree{}, false
+	}
+	target, _ = filepath.Abs(target)
+	if resolved, err := filepath.EvalSymlinks(target); err == nil {
+		target = resolved
+	}
+
+	for _, wt := range worktrees {
+		if wt.Path == target {
+			return wt, true
+		}
+	}
+	return repo.Worktree{}, false
+}
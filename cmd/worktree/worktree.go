@@ -0,0 +1,64 @@
+package worktree
+
+import (
+	"os"
+
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var Command = &cobra.Command{
+	Use:     "worktree",
+	Aliases: []string{"wt"},
+	Short:   "Manage worktrees",
+	Long: `Manages the current repository's worktrees.
+
+New worktrees are created according to the worktrees.layout config value, which defaults to "{repo}.worktrees/{branch}" next to the repository. Worktrees inside a search group show up in searches and can be jumped to like any repository.
+
+  gimme worktree add <branch>     - create a worktree and jump into it
+  gimme worktree list             - list worktrees with their branch and state
+  gimme worktree remove <branch>  - remove a worktree by branch or path
+  gimme worktree prune            - clean up worktrees whose directory is gone`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+func init() {
+	Command.AddCommand(addCommand)
+	Command.AddCommand(listCommand)
+	Command.AddCommand(removeCommand)
+	Command.AddCommand(pruneCommand)
+}
+
+// currentRepo returns the repository containing the working directory, logging
+// why when there isn't one.
+func currentRepo() *repo.Repo {
+	cwd, err := os.Getwd()
+	if err != nil {
+		log.Error("Could not determine current directory: {}", err)
+		return nil
+	}
+
+	currentRepo := search.FindRepoForPath(cwd)
+	if currentRepo == nil {
+		log.Print("Not in a git repository.")
+	}
+	return currentRepo
+}
+
+// currentWorktreeRoot returns the root of the worktree containing the working
+// directory, or "" if it can't be determined.
+func currentWorktreeRoot() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	loc, err := repo.Locate(cwd)
+	if err != nil {
+		return ""
+	}
+	return loc.WorktreeRoot
+}
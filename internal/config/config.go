@@ -1,18 +1,38 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/kernelle-soft/gimme/internal/log"
 	"github.com/kernelle-soft/gimme/internal/path"
 	"github.com/kernelle-soft/gimme/internal/slice"
+	"github.com/spf13/cast"
 	"github.com/spf13/viper"
 )
 
 // Config keys using nested structure:
 //
-//	search-folders: [...]
+//	search-folders:
+//	  - ~/src
+//	  - path: ~/work           (a search group with its own walk options)
+//	    max-depth: 3
+//	    exclude: [archive]
+//	    skip-hidden: true
+//	    follow-symlinks: true
+//	    hooks: {...}           (hooks for the group's repositories, as below)
+//	search:
+//	  parallelism: 8
+//	  timeout: 10s
+//	  max-depth: 0             (defaults for every search group)
+//	  exclude: [node_modules, ...]
+//	  skip-hidden: false
+//	  follow-symlinks: false
 //	pins:
 //	  repositories: [...]
 //	  branches:
@@ -20,9 +40,39 @@ import (
 //	    repositories:
 //	      github.com/user/repo: [branch1, branch2]
 //	aliases: {...}
+//	repositories:
+//	  github.com/user/repo:
+//	    entry: services/api    (subdirectory to land in when jumping to the repo)
+//	    on-enter: source .venv/bin/activate  (run by the shell after jumping to the repo)
+//	    tags: [backend, infra]  (for --select tag:backend)
+//	    hooks: {...}           (hooks for the repository, as below)
+//	worktrees:
+//	  layout: "{repo}.worktrees/{branch}"
+//	  on-missing-branch: none  (none, worktree or checkout; used by repo@branch jumps)
+//	clone:
+//	  root: ~/src              (where 'gimme clone' puts repositories; default: the first search group)
+//	  layout: "{host}/{owner}/{repo}"
+//	hooks:
+//	  timeout: 30s             (hooks still running after this are killed)
+//	  pre-clean: ./check.sh    (a command or a list of them, per event; see package hook)
+//	  post-jump: [...]
+//	  post-clone: [...]
 const (
-	keySearchFolders = "search-folders"
-	keyAliases       = "aliases"
+	keySearchFolders     = "search-folders"
+	keySearchParallelism = "search.parallelism"
+	keySearchTimeout     = "search.timeout"
+	keySearchMaxDepth    = "search.max-depth"
+	keySearchExclude     = "search.exclude"
+	keySearchSkipHidden  = "search.skip-hidden"
+	keySearchFollowLinks = "search.follow-symlinks"
+	keyAliases           = "aliases"
+	keyRepositories      = "repositories"
+	keyWorktreeLayout    = "worktrees.layout"
+	keyWorktreeOnMissing = "worktrees.on-missing-branch"
+	keyCloneRoot         = "clone.root"
+	keyCloneLayout       = "clone.layout"
+	keyHooks             = "hooks"
+	keyHooksTimeout      = "hooks.timeout"
 
 	// Nested pins keys
 	keyPinsRepositories        = "pins.repositories"
@@ -33,19 +83,36 @@ const (
 // Defaults
 var defaultSearchFolder = "~/"
 var defaultPinnedGlobalBranches = []string{"main", "master"}
+var defaultSearchParallelism = max(8, runtime.NumCPU())
+var defaultWorktreeLayout = "{repo}.worktrees/{branch}"
+var defaultCloneLayout = "{host}/{owner}/{repo}"
+var defaultHooksTimeout = "30s"
+var defaultSearchExclude = []string{"node_modules", "vendor", ".cache", "**/go/pkg/mod"}
 
 // isDefaultSearchFolder checks if the given groups match the default
-func isDefaultSearchFolder(groups []string) bool {
-	return groups[0] == defaultSearchFolder && len(groups) == 1
+func isDefaultSearchFolder(groups []any) bool {
+	return len(groups) == 1 && rawGroupPath(groups[0]) == defaultSearchFolder
 }
 
 func Load() {
 	// Set defaults
 	viper.SetDefault(keySearchFolders, []string{defaultSearchFolder})
+	viper.SetDefault(keySearchParallelism, defaultSearchParallelism)
+	viper.SetDefault(keySearchTimeout, 0)
+	viper.SetDefault(keySearchMaxDepth, 0)
+	viper.SetDefault(keySearchExclude, defaultSearchExclude)
+	viper.SetDefault(keySearchSkipHidden, false)
+	viper.SetDefault(keySearchFollowLinks, false)
 	viper.SetDefault(keyPinsRepositories, []string{})
 	viper.SetDefault(keyPinsBranchesGlobal, defaultPinnedGlobalBranches)
 	viper.SetDefault(keyPinsBranchesRepositores, map[string][]string{})
 	viper.SetDefault(keyAliases, map[string]string{})
+	viper.SetDefault(keyRepositories, map[string]any{})
+	viper.SetDefault(keyWorktreeLayout, defaultWorktreeLayout)
+	viper.SetDefault(keyWorktreeOnMissing, "none")
+	viper.SetDefault(keyCloneRoot, "")
+	viper.SetDefault(keyCloneLayout, defaultCloneLayout)
+	viper.SetDefault(keyHooksTimeout, defaultHooksTimeout)
 
 	// Config file location
 	viper.SetConfigName(".gimme.config")
@@ -60,22 +127,125 @@ func Load() {
 	}
 }
 
+// SearchGroup is a search folder and the rules for walking it.
+type SearchGroup struct {
+	Path           string
+	MaxDepth       int      // 0 means unlimited; 1 only looks at the folder's direct children
+	Exclude        []string // gitignore-style patterns, relative to Path
+	SkipHidden     bool
+	FollowSymlinks bool
+	Hooks          map[string][]string // commands by event, run for the group's repositories
+}
+
 // GetSearchFolders returns the list of search folders (groups)
 func GetSearchFolders() []string {
-	rawPaths := viper.GetStringSlice(keySearchFolders)
+	return slice.Map(GetSearchGroups(), func(group SearchGroup) string {
+		return group.Path
+	})
+}
 
-	return slice.Map(rawPaths, func(rawPath string) string {
-		normalized, err := path.Normalize(rawPath)
-		if err != nil {
-			log.Error("Error parsing search folder \"{}\". Error: {}", rawPath, err)
+// GetSearchGroups returns every search group with its walk options, falling
+// back to the global search defaults for options a group doesn't set.
+func GetSearchGroups() []SearchGroup {
+	return slice.Map(rawSearchFolders(), parseSearchGroup)
+}
+
+// GetSearchGroup returns the search group for folder, or a group with the
+// global defaults if folder isn't configured.
+func GetSearchGroup(folder string) SearchGroup {
+	for _, group := range GetSearchGroups() {
+		if group.Path == folder {
+			return group
 		}
-		return normalized
-	})
+	}
+	group := defaultSearchGroup()
+	group.Path = folder
+	return group
+}
+
+// defaultSearchGroup returns a search group with the global walk options.
+func defaultSearchGroup() SearchGroup {
+	return SearchGroup{
+		MaxDepth:       viper.GetInt(keySearchMaxDepth),
+		Exclude:        viper.GetStringSlice(keySearchExclude),
+		SkipHidden:     viper.GetBool(keySearchSkipHidden),
+		FollowSymlinks: viper.GetBool(keySearchFollowLinks),
+	}
+}
+
+// parseSearchGroup converts a raw search-folders entry into a SearchGroup.
+// Group excludes add to the global excludes; other options replace them.
+func parseSearchGroup(raw any) SearchGroup {
+	group := defaultSearchGroup()
+
+	rawPath := rawGroupPath(raw)
+	normalized, err := path.Normalize(rawPath)
+	if err != nil {
+		log.Error("Error parsing search folder \"{}\". Error: {}", rawPath, err)
+	}
+	group.Path = normalized
+
+	options, ok := raw.(map[string]any)
+	if !ok {
+		return group
+	}
+	if v, ok := options["max-depth"]; ok {
+		group.MaxDepth = cast.ToInt(v)
+	}
+	if v, ok := options["exclude"]; ok {
+		group.Exclude = append(slices.Clone(group.Exclude), cast.ToStringSlice(v)...)
+	}
+	if v, ok := options["skip-hidden"]; ok {
+		group.SkipHidden = cast.ToBool(v)
+	}
+	if v, ok := options["follow-symlinks"]; ok {
+		group.FollowSymlinks = cast.ToBool(v)
+	}
+	group.Hooks = parseHooks(options["hooks"])
+	return group
+}
+
+// rawSearchFolders returns the search-folders entries as written in the config.
+// Each entry is either a path or a map with a "path" key and walk options.
+func rawSearchFolders() []any {
+	switch v := viper.Get(keySearchFolders).(type) {
+	case []any:
+		return v
+	case []string:
+		return slice.Map(v, func(s string) any { return s })
+	}
+	return []any{}
+}
+
+// rawGroupPath returns the path of a raw search-folders entry.
+func rawGroupPath(raw any) string {
+	switch v := raw.(type) {
+	case string:
+		return v
+	case map[string]any:
+		return cast.ToString(v["path"])
+	}
+	return ""
+}
+
+// GetSearchParallelism returns how many directories discovery reads at once
+func GetSearchParallelism() int {
+	parallelism := viper.GetInt(keySearchParallelism)
+	if parallelism < 1 {
+		return 1
+	}
+	return parallelism
+}
+
+// GetSearchTimeout returns the time budget for discovering repositories.
+// Zero means no limit.
+func GetSearchTimeout() time.Duration {
+	return viper.GetDuration(keySearchTimeout)
 }
 
 // AddGroup adds a search group path
 func AddGroup(groupPath string) error {
-	groups := viper.GetStringSlice(keySearchFolders)
+	groups := rawSearchFolders()
 
 	// Normalize the path
 	normalized, err := path.Normalize(groupPath)
@@ -85,7 +255,7 @@ func AddGroup(groupPath string) error {
 
 	// Check if already exists
 	for _, g := range groups {
-		existingNorm, _ := path.Normalize(g)
+		existingNorm, _ := path.Normalize(rawGroupPath(g))
 		if existingNorm == normalized {
 			log.Print("Group already exists: \"{}\".", groupPath)
 			return nil
@@ -94,7 +264,7 @@ func AddGroup(groupPath string) error {
 
 	// If the only group is the default, replace it instead of appending
 	if isDefaultSearchFolder(groups) {
-		groups = []string{groupPath}
+		groups = []any{groupPath}
 	} else {
 		groups = append(groups, groupPath)
 	}
@@ -106,13 +276,13 @@ func AddGroup(groupPath string) error {
 
 // DeleteGroup removes a search group by path
 func DeleteGroup(groupPath string) error {
-	groups := viper.GetStringSlice(keySearchFolders)
+	groups := rawSearchFolders()
 	normalized, _ := path.Normalize(groupPath)
 
-	newGroups := []string{}
+	newGroups := []any{}
 	found := false
 	for _, g := range groups {
-		existingNorm, _ := path.Normalize(g)
+		existingNorm, _ := path.Normalize(rawGroupPath(g))
 		if existingNorm == normalized {
 			found = true
 			continue
@@ -137,14 +307,14 @@ func DeleteGroup(groupPath string) error {
 
 // DeleteGroupByIndex removes a search group by index
 func DeleteGroupByIndex(index int) error {
-	groups := viper.GetStringSlice(keySearchFolders)
+	groups := rawSearchFolders()
 
 	if index < 0 || index >= len(groups) {
 		log.Print("Index out of range: {} (have {} groups).", index, len(groups))
 		return nil
 	}
 
-	groupPath := groups[index]
+	groupPath := rawGroupPath(groups[index])
 	groups = append(groups[:index], groups[index+1:]...)
 	viper.Set(keySearchFolders, groups)
 	err := saveConfig()
@@ -499,6 +669,283 @@ func DeleteAlias(short string) error {
 	return nil
 }
 
+// =============================================================================
+// Repository settings (repositories.<identifier>)
+// Uses repo identifier (e.g. "github.com/user/repo") as key
+// =============================================================================
+
+// repoSettings returns the settings map of every configured repository.
+func repoSettings() map[string]map[string]any {
+	result := map[string]map[string]any{}
+	for repoID, raw := range viper.GetStringMap(keyRepositories) {
+		if settings, ok := raw.(map[string]any); ok {
+			result[repoID] = settings
+		}
+	}
+	return result
+}
+
+// setRepoSetting sets (or with a nil value, removes) one setting of a
+// repository and saves the config. Repositories left without settings are
+// dropped.
+func setRepoSetting(repoIdentifier, key string, value any) error {
+	all := repoSettings()
+	repoID := strings.ToLower(repoIdentifier)
+
+	settings := all[repoID]
+	if settings == nil {
+		settings = map[string]any{}
+	}
+	if value == nil {
+		delete(settings, key)
+	} else {
+		settings[key] = value
+	}
+
+	if len(settings) == 0 {
+		delete(all, repoID)
+	} else {
+		all[repoID] = settings
+	}
+
+	// Stored as map[string]any so repoSettings can read it back before a reload
+	repositories := map[string]any{}
+	for id, settings := range all {
+		repositories[id] = settings
+	}
+	viper.Set(keyRepositories, repositories)
+	return saveConfig()
+}
+
+// GetRepoEntries returns the map of repo identifier to entry subdirectory.
+func GetRepoEntries() map[string]string {
+	result := map[string]string{}
+	for repoID, settings := range repoSettings() {
+		if entry := cast.ToString(settings["entry"]); entry != "" {
+			result[repoID] = entry
+		}
+	}
+	return result
+}
+
+// GetRepoEntry returns the subdirectory to land in when jumping to a
+// repository, or "" to land in its root.
+func GetRepoEntry(repoIdentifier string) string {
+	return GetRepoEntries()[strings.ToLower(repoIdentifier)]
+}
+
+// SetRepoEntry sets the entry subdirectory for a repository.
+func SetRepoEntry(repoIdentifier, subdir string) error {
+	subdir = strings.Trim(filepath.ToSlash(subdir), "/")
+	if subdir == "" {
+		return DeleteRepoEntry(repoIdentifier)
+	}
+
+	err := setRepoSetting(repoIdentifier, "entry", subdir)
+	if err != nil {
+		log.Error("Error saving config. Error: {}", err)
+		return nil
+	}
+	log.Print("Set entry for repo \"{}\" to \"{}\".", repoIdentifier, subdir)
+	return nil
+}
+
+// DeleteRepoEntry removes the entry subdirectory of a repository.
+func DeleteRepoEntry(repoIdentifier string) error {
+	if GetRepoEntry(repoIdentifier) == "" {
+		log.Print("No entry set for repo \"{}\".", repoIdentifier)
+		return nil
+	}
+
+	err := setRepoSetting(repoIdentifier, "entry", nil)
+	if err != nil {
+		log.Error("Error saving config. Error: {}", err)
+		return nil
+	}
+	log.Print("Deleted entry for repo \"{}\".", repoIdentifier)
+	return nil
+}
+
+// GetRepoOnEnters returns the map of repo identifier to on-enter command.
+func GetRepoOnEnters() map[string]string {
+	result := map[string]string{}
+	for repoID, settings := range repoSettings() {
+		if command := cast.ToString(settings["on-enter"]); command != "" {
+			result[repoID] = command
+		}
+	}
+	return result
+}
+
+// GetRepoOnEnter returns the shell command to run after jumping to a
+// repository, or "" if there is none.
+func GetRepoOnEnter(repoIdentifier string) string {
+	return GetRepoOnEnters()[strings.ToLower(repoIdentifier)]
+}
+
+// SetRepoOnEnter sets the on-enter command for a repository.
+func SetRepoOnEnter(repoIdentifier, command string) error {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return DeleteRepoOnEnter(repoIdentifier)
+	}
+
+	err := setRepoSetting(repoIdentifier, "on-enter", command)
+	if err != nil {
+		log.Error("Error saving config. Error: {}", err)
+		return nil
+	}
+	log.Print("Set on-enter command for repo \"{}\" to \"{}\".", repoIdentifier, command)
+	return nil
+}
+
+// DeleteRepoOnEnter removes the on-enter command of a repository.
+func DeleteRepoOnEnter(repoIdentifier string) error {
+	if GetRepoOnEnter(repoIdentifier) == "" {
+		log.Print("No on-enter command set for repo \"{}\".", repoIdentifier)
+		return nil
+	}
+
+	err := setRepoSetting(repoIdentifier, "on-enter", nil)
+	if err != nil {
+		log.Error("Error saving config. Error: {}", err)
+		return nil
+	}
+	log.Print("Deleted on-enter command for repo \"{}\".", repoIdentifier)
+	return nil
+}
+
+// GetRepoTags returns the map of repo identifier to its tags.
+func GetRepoTags() map[string][]string {
+	result := map[string][]string{}
+	for repoID, settings := range repoSettings() {
+		if tags := cast.ToStringSlice(settings["tags"]); len(tags) > 0 {
+			result[repoID] = tags
+		}
+	}
+	return result
+}
+
+// GetTagsForRepo returns the tags of a repository.
+func GetTagsForRepo(repoIdentifier string) []string {
+	return GetRepoTags()[strings.ToLower(repoIdentifier)]
+}
+
+// AddRepoTag tags a repository.
+func AddRepoTag(repoIdentifier, tag string) error {
+	tags := GetTagsForRepo(repoIdentifier)
+	if slices.Contains(tags, tag) {
+		log.Print("Repo \"{}\" is already tagged \"{}\".", repoIdentifier, tag)
+		return nil
+	}
+
+	err := setRepoSetting(repoIdentifier, "tags", append(tags, tag))
+	if err != nil {
+		log.Error("Error saving config. Error: {}", err)
+		return nil
+	}
+	log.Print("Tagged repo \"{}\" \"{}\".", repoIdentifier, tag)
+	return nil
+}
+
+// DeleteRepoTag removes a tag from a repository.
+func DeleteRepoTag(repoIdentifier, tag string) error {
+	tags := GetTagsForRepo(repoIdentifier)
+	if !slices.Contains(tags, tag) {
+		log.Print("Repo \"{}\" isn't tagged \"{}\".", repoIdentifier, tag)
+		return nil
+	}
+
+	var value any
+	if remaining := slices.DeleteFunc(tags, func(t string) bool { return t == tag }); len(remaining) > 0 {
+		value = remaining
+	}
+	err := setRepoSetting(repoIdentifier, "tags", value)
+	if err != nil {
+		log.Error("Error saving config. Error: {}", err)
+		return nil
+	}
+	log.Print("Removed tag \"{}\" from repo \"{}\".", tag, repoIdentifier)
+	return nil
+}
+
+// =============================================================================
+// Worktrees
+// =============================================================================
+
+// GetWorktreeLayout returns the template for new worktree paths. It may use
+// {repo} and {branch}, and relative layouts sit next to the repository.
+func GetWorktreeLayout() string {
+	return viper.GetString(keyWorktreeLayout)
+}
+
+// GetWorktreeOnMissingBranch returns what a repo@branch jump does when no
+// worktree has the branch checked out: "none", "worktree" or "checkout".
+func GetWorktreeOnMissingBranch() string {
+	return viper.GetString(keyWorktreeOnMissing)
+}
+
+// =============================================================================
+// Cloning
+// =============================================================================
+
+// GetCloneRoot returns the directory 'gimme clone' lays repositories out in:
+// clone.root if set, otherwise the first search group.
+func GetCloneRoot() (string, error) {
+	if root := viper.GetString(keyCloneRoot); root != "" {
+		return path.Normalize(root)
+	}
+	folders := GetSearchFolders()
+	if len(folders) == 0 {
+		return "", errors.New("no clone.root or search groups configured")
+	}
+	return folders[0], nil
+}
+
+// GetCloneLayout returns the template for where clones go under the clone
+// root. It may use {host}, {owner}, {repo} and {identifier}.
+func GetCloneLayout() string {
+	return viper.GetString(keyCloneLayout)
+}
+
+// =============================================================================
+// Hooks
+// =============================================================================
+
+// GetHooks returns the commands configured globally for event, in order.
+func GetHooks(event string) []string {
+	return parseHooks(viper.Get(keyHooks))[event]
+}
+
+// GetRepoHooks returns the commands configured for event in one repository.
+func GetRepoHooks(repoIdentifier, event string) []string {
+	return parseHooks(repoSettings()[strings.ToLower(repoIdentifier)]["hooks"])[event]
+}
+
+// GetHooksTimeout returns how long a hook may run before it's killed. Zero
+// means no limit.
+func GetHooksTimeout() time.Duration {
+	return viper.GetDuration(keyHooksTimeout)
+}
+
+// parseHooks converts a raw hooks map into commands by event. Each event
+// takes a command or a list of them.
+func parseHooks(raw any) map[string][]string {
+	hooks := map[string][]string{}
+	for event, commands := range cast.ToStringMap(raw) {
+		if event == "timeout" {
+			continue
+		}
+		switch v := commands.(type) {
+		case string:
+			hooks[event] = []string{v}
+		case []any, []string:
+			hooks[event] = cast.ToStringSlice(v)
+		}
+	}
+	return hooks
+}
+
 // =============================================================================
 // Config persistence
 // =============================================================================
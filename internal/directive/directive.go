@@ -0,0 +1,232 @@
+// Package directive implements the protocol gimme uses to ask its shell
+// wrapper for things a child process can't do itself: change directory, set
+// or unset environment variables, and run commands in the calling shell.
+//
+// Wrappers that speak the protocol set GIMME_PROTOCOL to the highest version
+// they understand. gimme then prints a header line followed by one directive
+// per line:
+//
+//	#gimme:1
+//	cd /home/me/src/api
+//	setenv GIMME_REPO github.com/acme/api
+//	unsetenv GIMME_BRANCH
+//	run source .venv/bin/activate
+//	message Checked out "main".
+//
+// Arguments escape backslashes as \\ and newlines as \n, so every directive
+// fits on one line. Wrappers ignore directives they don't know.
+//
+// Older wrappers don't set GIMME_PROTOCOL and only understand a single
+// "cd://<path>" line, so for them gimme falls back to that.
+package directive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is the newest protocol version gimme speaks.
+const Version = 1
+
+// ProtocolEnv is set by the shell wrapper to the newest version it speaks.
+const ProtocolEnv = "GIMME_PROTOCOL"
+
+// Environment variables gimme sets in the shell after a jump, and in the
+// commands it runs in a repository.
+const (
+	RepoEnv   = "GIMME_REPO"
+	BranchEnv = "GIMME_BRANCH"
+
+	// EnvNamesEnv lists the variables set from a repository's env, so the
+	// next jump can unset them.
+	EnvNamesEnv = "GIMME_ENV"
+)
+
+// Legacy is the version of wrappers that only understand cd://.
+const Legacy = 0
+
+const (
+	KindCd       = "cd"
+	KindSetenv   = "setenv"
+	KindUnsetenv = "unsetenv"
+	KindRun      = "run"
+	KindMessage  = "message"
+)
+
+const headerPrefix = "#gimme:"
+
+var ErrInvalidName = errors.New("invalid environment variable name")
+
+// namePattern is what every supported shell accepts as a variable name.
+var namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// IsValidName reports whether name can be set or unset as an environment
+// variable by every supported shell.
+func IsValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// Directive is one instruction for the shell wrapper. Name is only used by
+// setenv and unsetenv.
+type Directive struct {
+	Kind  string
+	Name  string
+	Value string
+}
+
+// Cd changes the shell's working directory to path.
+func Cd(path string) Directive {
+	return Directive{Kind: KindCd, Value: path}
+}
+
+// Setenv exports name=value in the shell.
+func Setenv(name, value string) Directive {
+	return Directive{Kind: KindSetenv, Name: name, Value: value}
+}
+
+// Unsetenv removes name from the shell's environment.
+func Unsetenv(name string) Directive {
+	return Directive{Kind: KindUnsetenv, Name: name}
+}
+
+// Run evaluates command in the shell, after the directives before it.
+func Run(command string) Directive {
+	return Directive{Kind: KindRun, Value: command}
+}
+
+// Message prints text on the shell's stderr.
+func Message(text string) Directive {
+	return Directive{Kind: KindMessage, Value: text}
+}
+
+// Negotiate returns the protocol version to speak with the wrapper that ran
+// gimme, based on GIMME_PROTOCOL.
+func Negotiate() int {
+	wrapper, err := strconv.Atoi(os.Getenv(ProtocolEnv))
+	if err != nil || wrapper < Legacy {
+		return Legacy
+	}
+	return min(wrapper, Version)
+}
+
+// Emit writes directives to stdout in the version the wrapper speaks.
+// Messages and problems go to stderr.
+func Emit(directives ...Directive) {
+	if err := Write(os.Stdout, os.Stderr, Negotiate(), directives...); err != nil {
+		fmt.Fprintf(os.Stderr, "gimme: %v\n", err)
+	}
+}
+
+// Write encodes directives for protocol version to out. Legacy wrappers only
+// get the last cd; their messages go to errOut, and anything else is dropped
+// with a note there.
+func Write(out, errOut io.Writer, version int, directives ...Directive) error {
+	for _, d := range directives {
+		if (d.Kind == KindSetenv || d.Kind == KindUnsetenv) && !IsValidName(d.Name) {
+			return fmt.Errorf("%w %q", ErrInvalidName, d.Name)
+		}
+	}
+
+	if version == Legacy {
+		return writeLegacy(out, errOut, directives)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%d\n", headerPrefix, version)
+	for _, d := range directives {
+		b.WriteString(d.Kind)
+		if d.Name != "" {
+			b.WriteString(" " + d.Name)
+		}
+		if d.Value != "" {
+			b.WriteString(" " + escape(d.Value))
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+func writeLegacy(out, errOut io.Writer, directives []Directive) error {
+	cd, dropped := "", false
+	for _, d := range directives {
+		switch d.Kind {
+		case KindCd:
+			cd = d.Value
+		case KindMessage:
+			fmt.Fprintln(errOut, d.Value)
+		case KindRun:
+			dropped = true
+		}
+	}
+
+	if dropped {
+		fmt.Fprintln(errOut, "gimme: your shell integration is out of date and can't run commands. Reload it with 'gimme init'.")
+	}
+	if cd == "" {
+		return nil
+	}
+	_, err := fmt.Fprintf(out, "cd://%s\n", cd)
+	return err
+}
+
+// Parse reads directives written by Write in any version, including legacy
+// cd:// output. Unknown directives are skipped.
+func Parse(r io.Reader) ([]Directive, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	output := string(data)
+	if path, ok := strings.CutPrefix(output, "cd://"); ok {
+		// Legacy paths aren't escaped; only the final newline isn't theirs.
+		return []Directive{Cd(strings.TrimSuffix(path, "\n"))}, nil
+	}
+
+	header, body, _ := strings.Cut(output, "\n")
+	if !strings.HasPrefix(header, headerPrefix) {
+		return nil, fmt.Errorf("missing %q header", headerPrefix)
+	}
+
+	var directives []Directive
+	for _, line := range strings.Split(body, "\n") {
+		kind, arg, _ := strings.Cut(line, " ")
+		switch kind {
+		case KindCd, KindRun, KindMessage:
+			directives = append(directives, Directive{Kind: kind, Value: unescape(arg)})
+		case KindSetenv, KindUnsetenv:
+			name, value, _ := strings.Cut(arg, " ")
+			directives = append(directives, Directive{Kind: kind, Name: name, Value: unescape(value)})
+		}
+	}
+	return directives, nil
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+func unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if s[i] == 'n' {
+			b.WriteByte('\n')
+		} else {
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package directive
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriteAndParse(t *testing.T) {
+	directives := []Directive{
+		Cd("/src/with space/and\nnewline\n"),
+		Setenv("GIMME_REPO", `github.com/acme/api`),
+		Setenv("TRICKY", ` leading space, back\slash, literal \n and`+"\nnewline"),
+		Setenv("EMPTY", ""),
+		Unsetenv("GIMME_BRANCH"),
+		Run("source .venv/bin/activate"),
+		Message(`Checked out "main".`),
+	}
+
+	var out, errOut bytes.Buffer
+	if err := Write(&out, &errOut, Version, directives...); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if lines[0] != "#gimme:1" || len(lines) != len(directives)+1 {
+		t.Fatalf("Write() = %q, want a header and one line per directive", out.String())
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("Write() wrote %q to stderr, want nothing", errOut.String())
+	}
+
+	parsed, err := Parse(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(parsed, directives) {
+		t.Errorf("Parse() = %q, want %q", parsed, directives)
+	}
+
+	t.Run("unknown directives are skipped", func(t *testing.T) {
+		parsed, err := Parse(strings.NewReader("#gimme:1\nteleport /x\ncd /src\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(parsed, []Directive{Cd("/src")}) {
+			t.Errorf("Parse() = %q, want just the cd", parsed)
+		}
+	})
+}
+
+func TestWriteLegacy(t *testing.T) {
+	var out, errOut bytes.Buffer
+	err := Write(&out, &errOut, Legacy,
+		Cd("/src/api"),
+		Setenv("GIMME_REPO", "github.com/acme/api"),
+		Run("source .venv/bin/activate"),
+		Message("hello"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "cd:///src/api\n" {
+		t.Errorf("Write() = %q, want only the cd:// line", out.String())
+	}
+	if !strings.Contains(errOut.String(), "hello") || !strings.Contains(errOut.String(), "gimme init") {
+		t.Errorf("stderr = %q, want the message and a note about the dropped command", errOut.String())
+	}
+
+	parsed, err := Parse(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(parsed, []Directive{Cd("/src/api")}) {
+		t.Errorf("Parse() = %q, want the cd", parsed)
+	}
+}
+
+func TestWriteRejectsInvalidNames(t *testing.T) {
+	for _, name := range []string{"", "1ABC", "A-B", "A B", "A=B", "$(rm)"} {
+		var out bytes.Buffer
+		if err := Write(&out, &out, Version, Setenv(name, "x")); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Write(setenv %q) error = %v, want ErrInvalidName", name, err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("Write(setenv %q) wrote %q, want nothing", name, out.String())
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := map[string]int{
+		"":      Legacy,
+		"0":     Legacy,
+		"1":     1,
+		"99":    Version,
+		"-1":    Legacy,
+		"bogus": Legacy,
+	}
+	for env, want := range tests {
+		t.Setenv(ProtocolEnv, env)
+		if got := Negotiate(); got != want {
+			t.Errorf("Negotiate() with %s=%q = %d, want %d", ProtocolEnv, env, got, want)
+		}
+	}
+}
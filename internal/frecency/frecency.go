@@ -0,0 +1,169 @@
+package frecency
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kernelle-soft/gimme/internal/state"
+)
+
+const fileName = "frecency.json"
+
+// maxTotal caps the sum of all counts. Once exceeded, every count decays so old
+// favorites gradually make room for what's used now.
+const maxTotal = 1000
+
+// decayFactor is how much of its count an entry keeps each time the store ages.
+const decayFactor = 0.9
+
+// Entry records how often and how recently a path was jumped to.
+type Entry struct {
+	Path       string    `json:"path"`
+	Count      float64   `json:"count"`
+	LastAccess time.Time `json:"last_access"`
+}
+
+// Score weighs an entry's count by how recently it was used.
+func (e Entry) Score(now time.Time) float64 {
+	age := now.Sub(e.LastAccess)
+	switch {
+	case age < time.Hour:
+		return e.Count * 4
+	case age < 24*time.Hour:
+		return e.Count * 2
+	case age < 7*24*time.Hour:
+		return e.Count / 2
+	default:
+		return e.Count / 4
+	}
+}
+
+// Store is the persisted jump history used for frecency ranking.
+type Store struct {
+	path    string
+	entries map[string]*Entry
+}
+
+// Load reads the store from gimme's state directory.
+func Load() (*Store, error) {
+	path, err := state.File(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads the store from a specific file.
+func LoadFile(path string) (*Store, error) {
+	entries := []*Entry{}
+	if err := state.ReadJSON(path, &entries); err != nil {
+		return nil, err
+	}
+
+	s := &Store{path: path, entries: make(map[string]*Entry, len(entries))}
+	for _, e := range entries {
+		s.entries[e.Path] = e
+	}
+	return s, nil
+}
+
+// Record loads the store, adds a jump to path, and saves it.
+func Record(path string) error {
+	s, err := Load()
+	if err != nil {
+		return err
+	}
+	s.Add(path, time.Now())
+	return s.Save()
+}
+
+// Save writes the store back to the file it was loaded from.
+func (s *Store) Save() error {
+	return state.WriteJSON(s.path, s.sorted(time.Now()))
+}
+
+// Add records a jump to path at the given time, aging the store if needed.
+func (s *Store) Add(path string, now time.Time) {
+	entry, ok := s.entries[path]
+	if !ok {
+		entry = &Entry{Path: path}
+		s.entries[path] = entry
+	}
+	entry.Count++
+	entry.LastAccess = now
+
+	s.age()
+}
+
+// Score returns the frecency score for path, or 0 if it has never been visited.
+// It is safe to call on a nil store.
+func (s *Store) Score(path string, now time.Time) float64 {
+	if s == nil {
+		return 0
+	}
+	entry, ok := s.entries[path]
+	if !ok {
+		return 0
+	}
+	return entry.Score(now)
+}
+
+// Forget removes path from the store. Returns false if it wasn't there.
+func (s *Store) Forget(path string) bool {
+	if _, ok := s.entries[path]; !ok {
+		return false
+	}
+	delete(s.entries, path)
+	return true
+}
+
+// Clear removes every entry.
+func (s *Store) Clear() {
+	s.entries = map[string]*Entry{}
+}
+
+// Entries returns all entries, highest score first.
+func (s *Store) Entries(now time.Time) []Entry {
+	sorted := s.sorted(now)
+	result := make([]Entry, len(sorted))
+	for i, e := range sorted {
+		result[i] = *e
+	}
+	return result
+}
+
+// age decays every count once the total exceeds maxTotal, dropping entries
+// that fall below a single visit.
+func (s *Store) age() {
+	total := 0.0
+	for _, e := range s.entries {
+		total += e.Count
+	}
+	if total <= maxTotal {
+		return
+	}
+
+	factor := decayFactor * maxTotal / total
+	for path, e := range s.entries {
+		e.Count *= factor
+		if e.Count < 1 {
+			delete(s.entries, path)
+		}
+	}
+}
+
+func (s *Store) sorted(now time.Time) []*Entry {
+	result := make([]*Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		result = append(result, e)
+	}
+	slices.SortFunc(result, func(a, b *Entry) int {
+		if c := cmp.Compare(b.Score(now), a.Score(now)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	return result
+}
@@ -0,0 +1,115 @@
+package frecency
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) (*Store, func()) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "gimme-frecency-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := LoadFile(filepath.Join(tmpDir, fileName))
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatal(err)
+	}
+	return s, func() { os.RemoveAll(tmpDir) }
+}
+
+func TestEntryScore(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		age      time.Duration
+		expected float64
+	}{
+		{name: "within the hour", age: 10 * time.Minute, expected: 40},
+		{name: "within the day", age: 3 * time.Hour, expected: 20},
+		{name: "within the week", age: 3 * 24 * time.Hour, expected: 5},
+		{name: "older", age: 30 * 24 * time.Hour, expected: 2.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Entry{Path: "/src/repo", Count: 10, LastAccess: now.Add(-tt.age)}
+			if score := e.Score(now); score != tt.expected {
+				t.Errorf("Score() = %v, want %v", score, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	s, cleanup := newTestStore(t)
+	defer cleanup()
+
+	now := time.Now()
+	s.Add("/src/a", now)
+	s.Add("/src/b", now)
+	s.Add("/src/b", now)
+
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadFile(s.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := loaded.Entries(now)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Path != "/src/b" || entries[0].Count != 2 {
+		t.Errorf("Entries()[0] = %+v, want /src/b with count 2", entries[0])
+	}
+
+	t.Run("forget removes an entry", func(t *testing.T) {
+		if !loaded.Forget("/src/a") {
+			t.Error("Expected Forget to report the entry was removed")
+		}
+		if loaded.Forget("/src/a") {
+			t.Error("Expected a second Forget to report nothing was removed")
+		}
+		if loaded.Score("/src/a", now) != 0 {
+			t.Error("Expected forgotten entry to score 0")
+		}
+	})
+}
+
+func TestStoreAging(t *testing.T) {
+	s, cleanup := newTestStore(t)
+	defer cleanup()
+
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	s.Add("/src/rare", old)
+	for range maxTotal {
+		s.Add("/src/busy", time.Now())
+	}
+
+	total := 0.0
+	for _, e := range s.Entries(time.Now()) {
+		total += e.Count
+	}
+	if total > maxTotal {
+		t.Errorf("Total count = %v, want <= %d after aging", total, maxTotal)
+	}
+	if s.Score("/src/rare", time.Now()) != 0 {
+		t.Error("Expected entry with a single old visit to be dropped after aging")
+	}
+}
+
+func TestNilStoreScore(t *testing.T) {
+	var s *Store
+	if s.Score("/src/a", time.Now()) != 0 {
+		t.Error("Expected nil store to score 0")
+	}
+}
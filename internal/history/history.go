@@ -0,0 +1,177 @@
+package history
+
+import (
+	"os"
+	"slices"
+	"time"
+
+	"github.com/kernelle-soft/gimme/internal/state"
+)
+
+const fileName = "history.json"
+
+// SessionEnv names the environment variable the shell wrapper sets to tell
+// shells apart. Without it only the global history is kept.
+const SessionEnv = "GIMME_SESSION"
+
+const (
+	// maxSessionEntries and maxGlobalEntries cap how many visits are kept.
+	maxSessionEntries = 100
+	maxGlobalEntries  = 500
+
+	// sessionTTL is how long a session's history outlives its last visit.
+	// Shells don't tell us when they exit, so stale sessions are dropped by age.
+	sessionTTL = 7 * 24 * time.Hour
+)
+
+// Entry is a visit to a directory.
+type Entry struct {
+	Path string    `json:"path"`
+	Time time.Time `json:"time"`
+}
+
+// Store is the persisted history of visited directories, kept once globally
+// and once per shell session.
+type Store struct {
+	Global   []Entry            `json:"global"`
+	Sessions map[string][]Entry `json:"sessions"`
+
+	path string
+}
+
+// Load reads the store from gimme's state directory.
+func Load() (*Store, error) {
+	path, err := state.File(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads the store from a specific file.
+func LoadFile(path string) (*Store, error) {
+	s := &Store{}
+	if err := state.ReadJSON(path, s); err != nil {
+		return nil, err
+	}
+	if s.Sessions == nil {
+		s.Sessions = map[string][]Entry{}
+	}
+	s.path = path
+	return s, nil
+}
+
+// Session returns the current shell session, or "" outside the shell wrapper.
+func Session() string {
+	return os.Getenv(SessionEnv)
+}
+
+// Record loads the store, records a move from the working directory to path in
+// the current session, and saves it.
+func Record(path string) error {
+	s, err := Load()
+	if err != nil {
+		return err
+	}
+
+	from, err := os.Getwd()
+	if err != nil {
+		from = ""
+	}
+	s.Visit(Session(), from, path, time.Now())
+	return s.Save()
+}
+
+// Save writes the store back to the file it was loaded from.
+func (s *Store) Save() error {
+	return state.WriteJSON(s.path, s)
+}
+
+// Visit records a move from one directory to another. from is recorded too,
+// so the first jump in a session can be undone. Either may be empty.
+func (s *Store) Visit(session, from, to string, now time.Time) {
+	for _, path := range []string{from, to} {
+		if path == "" {
+			continue
+		}
+		s.Global = appendVisit(s.Global, Entry{Path: path, Time: now}, maxGlobalEntries)
+		if session != "" {
+			s.Sessions[session] = appendVisit(s.Sessions[session], Entry{Path: path, Time: now}, maxSessionEntries)
+		}
+	}
+	s.expire(now)
+}
+
+// Recent returns previously visited directories, most recent first, without
+// duplicates and without current. The session's history is used when there is
+// one, the global history otherwise.
+func (s *Store) Recent(session, current string) []Entry {
+	entries := s.Global
+	if session != "" && len(s.Sessions[session]) > 0 {
+		entries = s.Sessions[session]
+	}
+
+	seen := map[string]bool{current: true}
+	recent := []Entry{}
+	for _, e := range slices.Backward(entries) {
+		if seen[e.Path] {
+			continue
+		}
+		seen[e.Path] = true
+		recent = append(recent, e)
+	}
+	return recent
+}
+
+// Reachable is Recent without the directories that no longer exist. It's
+// what 'gimme history' lists and 'gimme back' counts through, so their
+// numbers agree.
+func (s *Store) Reachable(session, current string) []Entry {
+	return slices.DeleteFunc(s.Recent(session, current), func(e Entry) bool {
+		info, err := os.Stat(e.Path)
+		return err != nil || !info.IsDir()
+	})
+}
+
+// Back returns the directory n steps back from current in Reachable. Returns
+// false if the history doesn't go back that far.
+func (s *Store) Back(session, current string, n int) (string, bool) {
+	reachable := s.Reachable(session, current)
+	if n < 1 || n > len(reachable) {
+		return "", false
+	}
+	return reachable[n-1].Path, true
+}
+
+// Clear removes the history of session, or all history if session is empty.
+func (s *Store) Clear(session string) {
+	if session != "" {
+		delete(s.Sessions, session)
+		return
+	}
+	s.Global = nil
+	s.Sessions = map[string][]Entry{}
+}
+
+// expire drops sessions that haven't been used within sessionTTL.
+func (s *Store) expire(now time.Time) {
+	for session, entries := range s.Sessions {
+		if len(entries) == 0 || now.Sub(entries[len(entries)-1].Time) > sessionTTL {
+			delete(s.Sessions, session)
+		}
+	}
+}
+
+// appendVisit adds e unless it repeats the last entry, keeping at most limit
+// entries.
+func appendVisit(entries []Entry, e Entry, limit int) []Entry {
+	if n := len(entries); n > 0 && entries[n-1].Path == e.Path {
+		entries[n-1].Time = e.Time
+		return entries
+	}
+	entries = append(entries, e)
+	if len(entries) > limit {
+		entries = slices.Clone(entries[len(entries)-limit:])
+	}
+	return entries
+}
@@ -0,0 +1,182 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) (*Store, string, func()) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "gimme-history-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := LoadFile(filepath.Join(tmpDir, fileName))
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatal(err)
+	}
+	return s, tmpDir, func() { os.RemoveAll(tmpDir) }
+}
+
+func paths(entries []Entry) []string {
+	result := make([]string, len(entries))
+	for i, e := range entries {
+		result[i] = e.Path
+	}
+	return result
+}
+
+func TestRecent(t *testing.T) {
+	s, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	now := time.Now()
+	s.Visit("one", "/home", "/src/a", now)
+	s.Visit("one", "/src/a", "/src/b", now)
+	s.Visit("one", "/src/b", "/src/a", now)
+	s.Visit("two", "/tmp", "/src/c", now)
+
+	tests := []struct {
+		name     string
+		session  string
+		current  string
+		expected []string
+	}{
+		{name: "session, most recent first, without duplicates", session: "one", current: "/src/a", expected: []string{"/src/b", "/home"}},
+		{name: "sessions are separate", session: "two", current: "/src/c", expected: []string{"/tmp"}},
+		{name: "global history covers every session", session: "", current: "/src/c", expected: []string{"/tmp", "/src/a", "/src/b", "/home"}},
+		{name: "unknown session falls back to global", session: "three", current: "/elsewhere", expected: []string{"/src/c", "/tmp", "/src/a", "/src/b", "/home"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := paths(s.Recent(tt.session, tt.current)); !slices.Equal(got, tt.expected) {
+				t.Errorf("Recent(%q, %q) = %v, want %v", tt.session, tt.current, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBack(t *testing.T) {
+	s, tmpDir, cleanup := newTestStore(t)
+	defer cleanup()
+
+	dirs := map[string]string{}
+	for _, name := range []string{"a", "b", "c"} {
+		dirs[name] = filepath.Join(tmpDir, name)
+		if err := os.Mkdir(dirs[name], 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	s.Visit("s", dirs["a"], dirs["b"], now)
+	s.Visit("s", dirs["b"], dirs["c"], now)
+
+	t.Run("one step back", func(t *testing.T) {
+		if got, ok := s.Back("s", dirs["c"], 1); !ok || got != dirs["b"] {
+			t.Errorf("Back(1) = %q, %v, want %q", got, ok, dirs["b"])
+		}
+	})
+
+	t.Run("going back twice returns to the start", func(t *testing.T) {
+		s.Visit("s", dirs["c"], dirs["b"], now)
+		if got, _ := s.Back("s", dirs["b"], 1); got != dirs["c"] {
+			t.Errorf("Back(1) = %q, want %q", got, dirs["c"])
+		}
+	})
+
+	t.Run("further back", func(t *testing.T) {
+		if got, ok := s.Back("s", dirs["b"], 2); !ok || got != dirs["a"] {
+			t.Errorf("Back(2) = %q, %v, want %q", got, ok, dirs["a"])
+		}
+	})
+
+	t.Run("missing directories are skipped", func(t *testing.T) {
+		if err := os.Remove(dirs["c"]); err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := s.Back("s", dirs["b"], 1); !ok || got != dirs["a"] {
+			t.Errorf("Back(1) = %q, %v, want %q", got, ok, dirs["a"])
+		}
+	})
+
+	t.Run("not that far", func(t *testing.T) {
+		if _, ok := s.Back("s", dirs["b"], 5); ok {
+			t.Error("Expected Back(5) to fail")
+		}
+	})
+}
+
+func TestReachableMatchesBack(t *testing.T) {
+	s, tmpDir, cleanup := newTestStore(t)
+	defer cleanup()
+
+	dirs := map[string]string{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		dirs[name] = filepath.Join(tmpDir, name)
+		if err := os.Mkdir(dirs[name], 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	s.Visit("s", dirs["a"], dirs["b"], now)
+	s.Visit("s", dirs["b"], dirs["c"], now)
+	if err := os.Remove(dirs["b"]); err != nil {
+		t.Fatal(err)
+	}
+	s.Visit("s", dirs["c"], dirs["d"], now)
+
+	reachable := paths(s.Reachable("s", dirs["d"]))
+	if expected := []string{dirs["c"], dirs["a"]}; !slices.Equal(reachable, expected) {
+		t.Fatalf("Reachable() = %v, want %v", reachable, expected)
+	}
+	for i, want := range reachable {
+		if got, ok := s.Back("s", dirs["d"], i+1); !ok || got != want {
+			t.Errorf("Back(%d) = %q, %v, want history entry %d, %q", i+1, got, ok, i+1, want)
+		}
+	}
+	if _, ok := s.Back("s", dirs["d"], len(reachable)+1); ok {
+		t.Errorf("Back(%d) succeeded past the end of the history", len(reachable)+1)
+	}
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	s, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	now := time.Now()
+	s.Visit("s", "/src/a", "/src/b", now)
+	s.Visit("old", "/src/x", "/src/y", now.Add(-30*24*time.Hour))
+	s.Visit("s", "/src/b", "/src/b", now)
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadFile(s.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := paths(loaded.Sessions["s"]); !slices.Equal(got, []string{"/src/a", "/src/b"}) {
+		t.Errorf("Sessions[s] = %v, want repeated visits collapsed", got)
+	}
+	if _, ok := loaded.Sessions["old"]; ok {
+		t.Error("Expected the stale session to be dropped")
+	}
+
+	loaded.Clear("s")
+	if _, ok := loaded.Sessions["s"]; ok || len(loaded.Global) == 0 {
+		t.Error("Expected Clear(session) to drop only that session")
+	}
+	loaded.Clear("")
+	if len(loaded.Global) != 0 || len(loaded.Sessions) != 0 {
+		t.Error("Expected Clear(\"\") to drop everything")
+	}
+}
@@ -0,0 +1,109 @@
+// Package hook runs the shell commands users configure for gimme's events.
+//
+// Hooks are set in the config globally, per search group and per repository
+// (see package config), and run in that order, each with sh in the root of
+// the repository's working tree. Their output goes to stderr. Besides gimme's
+// own environment they get:
+//
+//	GIMME_HOOK       the event, e.g. "pre-clean"
+//	GIMME_REPO       the repository's identifier
+//	GIMME_REPO_PATH  the root of its working tree
+//	GIMME_BRANCH     the branch the event is about: the one being deleted for
+//	                 pre-clean, otherwise the one checked out
+//
+// A hook that exits non-zero, or runs past hooks.timeout and is killed, fails
+// and the hooks after it don't run. A failing pre- hook vetoes the action.
+package hook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/directive"
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/path"
+)
+
+// Events hooks can run at.
+const (
+	PreClean  = "pre-clean"  // before 'gimme clean' deletes a branch; failing keeps it
+	PostJump  = "post-jump"  // after a jump to a repository
+	PostClone = "post-clone" // after 'gimme clone' or 'gimme sync-workspace' clones a repository
+)
+
+// Environment variables hooks get besides directive.RepoEnv and directive.BranchEnv.
+const (
+	EventEnv = "GIMME_HOOK"
+	PathEnv  = "GIMME_REPO_PATH"
+)
+
+// Target is the repository an event happened in.
+type Target struct {
+	Path       string // root of the working tree hooks run in
+	Identifier string
+	Branch     string
+}
+
+// Commands returns the commands configured for event in target: the global
+// ones, then those of the search groups containing it, then the repository's.
+func Commands(event string, target Target) []string {
+	commands := config.GetHooks(event)
+	for _, group := range config.GetSearchGroups() {
+		if path.IsWithin(target.Path, group.Path) {
+			commands = append(commands, group.Hooks[event]...)
+		}
+	}
+	return append(commands, config.GetRepoHooks(target.Identifier, event)...)
+}
+
+// Run runs the hooks for event in target, one after another, and returns the
+// first failure.
+func Run(event string, target Target) error {
+	for _, command := range Commands(event, target) {
+		log.Debug("Running {} hook: {}", event, command)
+		if err := run(event, command, target); err != nil {
+			return fmt.Errorf("%s hook %q %w", event, command, err)
+		}
+	}
+	return nil
+}
+
+// run runs one hook command, killing it after hooks.timeout.
+func run(event, command string, target Target) error {
+	ctx := context.Background()
+	timeout := config.GetHooksTimeout()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	c := exec.CommandContext(ctx, "sh", "-c", command)
+	killOnCancel(c)
+	c.Dir = target.Path
+	c.Env = append(os.Environ(),
+		EventEnv+"="+event,
+		directive.RepoEnv+"="+target.Identifier,
+		PathEnv+"="+target.Path,
+		directive.BranchEnv+"="+target.Branch,
+	)
+	// Stdout carries directives to the shell wrapper
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+
+	err := c.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		return fmt.Errorf("timed out after %s", timeout)
+	case errors.As(err, &exitErr):
+		return fmt.Errorf("exited with %d", exitErr.ExitCode())
+	case err != nil:
+		return fmt.Errorf("could not run: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package hook
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestRun(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-hook-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	defer viper.Reset()
+
+	work := filepath.Join(tmpDir, "work")
+	repoPath := filepath.Join(work, "api")
+	if err := os.MkdirAll(repoPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	logFile := filepath.Join(tmpDir, "hooks.log")
+	record := func(name string) string {
+		return `echo "` + name + ` $GIMME_HOOK $GIMME_REPO $GIMME_BRANCH $(pwd)" >> ` + logFile
+	}
+
+	viper.Set("hooks", map[string]any{
+		"pre-clean": record("global"),
+		"post-jump": []any{record("first"), "exit 3", record("never")},
+	})
+	viper.Set("search-folders", []any{
+		map[string]any{"path": work, "hooks": map[string]any{"pre-clean": record("group")}},
+		filepath.Join(tmpDir, "oss"),
+	})
+	viper.Set("repositories", map[string]any{
+		"github.com/acme/api": map[string]any{"hooks": map[string]any{"pre-clean": []string{record("repo")}}},
+	})
+	target := Target{Path: repoPath, Identifier: "github.com/acme/api", Branch: "feature"}
+
+	if err := Run(PreClean, target); err != nil {
+		t.Fatalf("pre-clean: unexpected error: %v", err)
+	}
+	suffix := " pre-clean github.com/acme/api feature " + repoPath
+	expected := "global" + suffix + "\ngroup" + suffix + "\nrepo" + suffix + "\n"
+	if got := readFile(t, logFile); got != expected {
+		t.Errorf("pre-clean ran:\n%s\nwant:\n%s", got, expected)
+	}
+
+	os.Remove(logFile)
+	err = Run(PostJump, target)
+	if err == nil || !strings.Contains(err.Error(), "exited with 3") {
+		t.Errorf("post-jump: got error %v, want one for exit 3", err)
+	}
+	if got := readFile(t, logFile); !strings.HasPrefix(got, "first ") || strings.Contains(got, "never") {
+		t.Errorf("post-jump: hooks after the failing one should not run, got:\n%s", got)
+	}
+
+	if err := Run(PostClone, target); err != nil {
+		t.Errorf("post-clone without hooks: unexpected error: %v", err)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("hooks", map[string]any{"timeout": "100ms", "pre-clean": "sleep 5"})
+
+	start := time.Now()
+	err := Run(PreClean, Target{Path: os.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("got error %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("hook ran for %s, should have been killed after 100ms", elapsed)
+	}
+}
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package hook
+
+import "os/exec"
+
+// killOnCancel leaves c to exec.CommandContext, which only kills the hook's
+// own process.
+func killOnCancel(c *exec.Cmd) {}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package hook
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killOnCancel makes c run in a process group of its own and kill the whole
+// group when cancelled, so commands the hook started don't outlive it.
+func killOnCancel(c *exec.Cmd) {
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	c.Cancel = func() error {
+		return syscall.Kill(-c.Process.Pid, syscall.SIGKILL)
+	}
+}
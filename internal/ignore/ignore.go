@@ -0,0 +1,151 @@
+package ignore
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// FileName is the per-directory ignore file honored during discovery.
+const FileName = ".gimmeignore"
+
+// rule is a single gitignore-style pattern.
+type rule struct {
+	base     string   // directory the pattern is relative to
+	segments []string // pattern split on "/", may contain "**"
+	anchored bool     // contains a "/", so it matches from base rather than at any depth
+	negate   bool
+}
+
+// Matcher decides whether paths are ignored. The zero value and nil ignore
+// nothing. Matchers are immutable, so one can be shared by concurrent walks.
+type Matcher struct {
+	rules []rule
+}
+
+// Add returns a new matcher with patterns appended. Patterns use gitignore
+// syntax relative to base:
+//
+//	node_modules    matches a directory named node_modules at any depth
+//	/build          matches only base/build
+//	go/pkg/mod      matches base/go/pkg/mod
+//	**/cache        matches cache at any depth
+//	archive/**      matches everything under base/archive
+//	!keep           re-includes keep if an earlier pattern ignored it
+//
+// Later patterns take precedence over earlier ones.
+func (m *Matcher) Add(base string, patterns []string) *Matcher {
+	var rules []rule
+	if m != nil {
+		rules = append(rules, m.rules...)
+	}
+
+	for _, p := range patterns {
+		if r, ok := parse(base, p); ok {
+			rules = append(rules, r)
+		}
+	}
+	return &Matcher{rules: rules}
+}
+
+// Match reports whether path is ignored.
+func (m *Matcher) Match(p string) bool {
+	if m == nil {
+		return false
+	}
+
+	ignored := false
+	for _, r := range m.rules {
+		if r.match(p) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// ReadFile reads patterns from an ignore file, skipping blank lines and
+// comments.
+func ReadFile(name string) ([]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	patterns := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
+
+func parse(base, pattern string) (rule, bool) {
+	r := rule{base: filepath.Clean(base)}
+
+	pattern = strings.TrimSpace(pattern)
+	if strings.HasPrefix(pattern, "!") {
+		r.negate = true
+		pattern = pattern[1:]
+	} else if strings.HasPrefix(pattern, `\`) {
+		pattern = pattern[1:]
+	}
+
+	// Everything discovery matches is a directory, so a trailing slash
+	// changes nothing.
+	pattern = strings.TrimSuffix(pattern, "/")
+	if pattern == "" {
+		return r, false
+	}
+
+	r.anchored = strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	r.segments = strings.Split(pattern, "/")
+	return r, true
+}
+
+func (r rule) match(p string) bool {
+	rel, err := filepath.Rel(r.base, p)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+
+	if !r.anchored {
+		// Unanchored patterns match against the final path element only.
+		ok, _ := path.Match(r.segments[0], parts[len(parts)-1])
+		return ok
+	}
+	return matchSegments(r.segments, parts)
+}
+
+// matchSegments matches path parts against pattern segments, where "**"
+// matches zero or more parts.
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
@@ -0,0 +1,86 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		expected bool
+	}{
+		{name: "name at top level", patterns: []string{"node_modules"}, path: "/home/node_modules", expected: true},
+		{name: "name at any depth", patterns: []string{"node_modules"}, path: "/home/web/app/node_modules", expected: true},
+		{name: "name doesn't match partially", patterns: []string{"node"}, path: "/home/node_modules", expected: false},
+		{name: "glob name", patterns: []string{"*.bak"}, path: "/home/src/repo.bak", expected: true},
+		{name: "trailing slash", patterns: []string{"vendor/"}, path: "/home/src/vendor", expected: true},
+		{name: "leading slash anchors", patterns: []string{"/build"}, path: "/home/src/build", expected: false},
+		{name: "leading slash matches at base", patterns: []string{"/build"}, path: "/home/build", expected: true},
+		{name: "nested path is anchored", patterns: []string{"go/pkg/mod"}, path: "/home/go/pkg/mod", expected: true},
+		{name: "nested path doesn't float", patterns: []string{"go/pkg/mod"}, path: "/home/x/go/pkg/mod", expected: false},
+		{name: "leading double star floats", patterns: []string{"**/go/pkg/mod"}, path: "/home/x/go/pkg/mod", expected: true},
+		{name: "trailing double star", patterns: []string{"archive/**"}, path: "/home/archive/old/repo", expected: true},
+		{name: "middle double star", patterns: []string{"a/**/z"}, path: "/home/a/b/c/z", expected: true},
+		{name: "negation re-includes", patterns: []string{"*.bak", "!keep.bak"}, path: "/home/keep.bak", expected: false},
+		{name: "later pattern wins", patterns: []string{"!keep", "keep"}, path: "/home/keep", expected: true},
+		{name: "outside base", patterns: []string{"src"}, path: "/elsewhere/src", expected: false},
+		{name: "base itself", patterns: []string{"home"}, path: "/home", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := (*Matcher)(nil).Add("/home", tt.patterns)
+			if got := m.Match(tt.path); got != tt.expected {
+				t.Errorf("Match(%q) with %v = %v, want %v", tt.path, tt.patterns, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAddKeepsParentUnchanged(t *testing.T) {
+	parent := (*Matcher)(nil).Add("/home", []string{"a"})
+	child := parent.Add("/home/src", []string{"b"})
+
+	if parent.Match("/home/src/b") {
+		t.Error("Expected parent matcher to be unaffected by Add")
+	}
+	if !child.Match("/home/src/b") || !child.Match("/home/src/a") {
+		t.Error("Expected child matcher to apply both parent and its own patterns")
+	}
+}
+
+func TestNilMatcher(t *testing.T) {
+	var m *Matcher
+	if m.Match("/home/anything") {
+		t.Error("Expected nil matcher to ignore nothing")
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-ignore-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	file := filepath.Join(tmpDir, FileName)
+	content := "# build output\nbuild/\n\n  scratch  \n!scratch/keep\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	patterns, err := ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"build/", "scratch", "!scratch/keep"}
+	if !slices.Equal(patterns, expected) {
+		t.Errorf("ReadFile() = %v, want %v", patterns, expected)
+	}
+}
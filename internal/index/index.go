@@ -0,0 +1,125 @@
+package index
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kernelle-soft/gimme/internal/state"
+)
+
+const fileName = "index.json"
+
+// version is bumped whenever the on-disk format changes. Indexes written with a
+// different version are discarded and rebuilt.
+const version = 3
+
+// Entry is a repository found during discovery.
+type Entry struct {
+	Path       string `json:"path"`
+	Name       string `json:"name"`
+	Identifier string `json:"identifier"`
+	Parent     string `json:"parent,omitempty"` // main repository of a linked worktree
+}
+
+// Dir is what discovery learned from reading one directory. It stays valid as
+// long as the directory's modification time doesn't change.
+type Dir struct {
+	ModTime       time.Time  `json:"mtime"`
+	Self          *Entry     `json:"self,omitempty"` // set when the directory is itself a repository
+	Repos         []Entry    `json:"repos,omitempty"`
+	Subdirs       []string   `json:"subdirs,omitempty"`
+	Links         []string   `json:"links,omitempty"`  // symlinks to directories
+	Ignore        []string   `json:"ignore,omitempty"` // patterns from the directory's .gimmeignore
+	IgnoreModTime *time.Time `json:"ignore_mtime,omitempty"`
+}
+
+// Index is the persisted cache of discovered repositories, keyed by directory.
+// Get and Put are safe for concurrent use.
+type Index struct {
+	Version int             `json:"version"`
+	Dirs    map[string]*Dir `json:"dirs"`
+
+	mu    sync.Mutex
+	path  string
+	dirty bool
+}
+
+// Load reads the index from gimme's cache directory.
+func Load() (*Index, error) {
+	path, err := state.CacheFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads the index from a specific file. A missing or outdated file
+// yields an empty index.
+func LoadFile(path string) (*Index, error) {
+	idx := &Index{}
+	if err := state.ReadJSON(path, idx); err != nil {
+		return nil, err
+	}
+
+	if idx.Version != version || idx.Dirs == nil {
+		idx = &Index{Version: version, Dirs: map[string]*Dir{}}
+	}
+	idx.path = path
+	return idx, nil
+}
+
+// Save writes the index back to its file if anything changed.
+func (idx *Index) Save() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if !idx.dirty {
+		return nil
+	}
+	if err := state.WriteJSON(idx.path, idx); err != nil {
+		return err
+	}
+	idx.dirty = false
+	return nil
+}
+
+// Remove deletes the index file and empties the index.
+func (idx *Index) Remove() error {
+	idx.Reset()
+	idx.dirty = false
+	err := os.Remove(idx.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Reset empties the index in memory.
+func (idx *Index) Reset() {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.Dirs = map[string]*Dir{}
+	idx.dirty = true
+}
+
+// Path returns the file the index is stored in.
+func (idx *Index) Path() string {
+	return idx.path
+}
+
+// Get returns the cached record for dir, if any.
+func (idx *Index) Get(dir string) (*Dir, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	d, ok := idx.Dirs[dir]
+	return d, ok
+}
+
+// Put stores the record for dir.
+func (idx *Index) Put(dir string, d *Dir) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.Dirs[dir] = d
+	idx.dirty = true
+}
@@ -9,18 +9,19 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/log"
+	"github.com/kernelle-soft/gimme/internal/directive"
 )
 
 var placeholderPattern = regexp.MustCompile(`\{\w*\}`)
 
 type logger struct {
-	stdout *log.Logger
 	stderr *log.Logger
 	debug  *log.Logger
 }
 
+// ToStdout asks the shell wrapper to change directory to path.
 func (l *logger) ToStdout(path string) {
-	l.stdout.Printf("cd://%s\n", path)
+	directive.Emit(directive.Cd(path))
 }
 
 func (l *logger) Error(msg any, args ...any) {
@@ -49,9 +50,6 @@ func (l *logger) Fatal(msg any, args ...any) {
 
 func newLogger() *logger {
 	return &logger{
-		stdout: log.NewWithOptions(os.Stdout, log.Options{
-			ReportCaller: false,
-		}),
 		stderr: log.NewWithOptions(os.Stderr, log.Options{
 			ReportCaller: false,
 		}),
@@ -0,0 +1,150 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/kernelle-soft/gimme/internal/path"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/spf13/viper"
+)
+
+// DefaultPath is where 'gimme sync-workspace' looks for a manifest when it
+// isn't given one, next to the config file.
+const DefaultPath = "~/.gimme.workspace.yaml"
+
+// Manifest declares the repositories a workspace should have and how gimme
+// treats them:
+//
+//	groups: [~/work]              (search groups to add)
+//	protected: [develop]          (branches protected in every repository)
+//	repositories:
+//	  - url: git@github.com:acme/api.git
+//	    group: ~/work             (where to clone it, by the clone layout; default: clone.root)
+//	    path: ~/work/api          (exactly where to clone it, instead of group)
+//	    pinned: true
+//	    aliases: [api]
+//	    protected: [release]      (branches protected in this repository)
+//
+// Relative paths are relative to the manifest's directory, so a manifest can
+// be checked into a team repository. Since whoever can change that repository
+// can change the manifest, a repository's group and path must be inside
+// clone.root or a search group the user already has (see Confine).
+type Manifest struct {
+	Groups       []string     `mapstructure:"groups"`
+	Protected    []string     `mapstructure:"protected"`
+	Repositories []Repository `mapstructure:"repositories"`
+}
+
+// Repository is a repository the workspace should have.
+type Repository struct {
+	URL       string   `mapstructure:"url"`
+	Group     string   `mapstructure:"group"`
+	Path      string   `mapstructure:"path"`
+	Pinned    bool     `mapstructure:"pinned"`
+	Aliases   []string `mapstructure:"aliases"`
+	Protected []string `mapstructure:"protected"`
+}
+
+// Identifier returns the repository's identifier, e.g. "github.com/acme/api",
+// or an empty string if its URL doesn't have one.
+func (r Repository) Identifier() string {
+	return repo.NormalizeRemoteURL(r.URL)
+}
+
+// Destination returns where the repository should be cloned: its path if set,
+// otherwise its place in layout under its group, or under root if it has none.
+func (r Repository) Destination(root, layout string) (string, error) {
+	if r.Path != "" {
+		return r.Path, nil
+	}
+	if r.Group != "" {
+		root = r.Group
+	}
+	return repo.ClonePath(r.Identifier(), root, layout)
+}
+
+// Confine checks that every repository in the manifest is cloned inside one
+// of dirs, reporting each one that isn't.
+func (m *Manifest) Confine(dirs []string) error {
+	errs := []error{}
+	for _, r := range m.Repositories {
+		for _, p := range []string{r.Group, r.Path} {
+			if p != "" && !slices.ContainsFunc(dirs, func(dir string) bool { return path.IsWithin(p, dir) }) {
+				errs = append(errs, fmt.Errorf("%s: %q is outside clone.root and the search groups", r.URL, p))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Load reads and validates the manifest at file.
+func Load(file string) (*Manifest, error) {
+	file, err := path.Normalize(file)
+	if err != nil {
+		return nil, err
+	}
+	if file, err = filepath.Abs(file); err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	v.SetConfigFile(file)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	if err := v.Unmarshal(m); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	dir := filepath.Dir(file)
+	seen := map[string]bool{}
+	for i := range m.Groups {
+		if m.Groups[i], err = resolve(dir, m.Groups[i]); err != nil {
+			return nil, err
+		}
+	}
+	for i := range m.Repositories {
+		r := &m.Repositories[i]
+		if r.URL == "" {
+			return nil, fmt.Errorf("%s: repository %d has no url", file, i+1)
+		}
+		if r.Identifier() == "" && r.Path == "" {
+			return nil, fmt.Errorf("%s: %q has no host and owner to lay it out by; give it a path", file, r.URL)
+		}
+		if id := r.Identifier(); id != "" {
+			if seen[id] {
+				return nil, fmt.Errorf("%s: %s is listed twice", file, id)
+			}
+			seen[id] = true
+		}
+		if r.Group, err = resolve(dir, r.Group); err != nil {
+			return nil, err
+		}
+		if r.Path, err = resolve(dir, r.Path); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// resolve normalizes p and makes it absolute, relative to dir. Empty paths
+// stay empty.
+func resolve(dir, p string) (string, error) {
+	if p == "" {
+		return "", nil
+	}
+	normalized, err := path.Normalize(p)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(normalized) {
+		normalized = filepath.Join(dir, normalized)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,132 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "gimme-manifest-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	file := filepath.Join(dir, "workspace.yaml")
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoad(t *testing.T) {
+	file := writeManifest(t, `
+groups: [work]
+protected: [develop]
+repositories:
+  - url: git@github.com:acme/api.git
+    group: work
+    pinned: true
+    aliases: [a]
+    protected: [release]
+  - url: /srv/git/tools.git
+    path: /opt/tools
+`)
+	dir := filepath.Dir(file)
+
+	m, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Manifest{
+		Groups:    []string{filepath.Join(dir, "work")},
+		Protected: []string{"develop"},
+		Repositories: []Repository{
+			{
+				URL:       "git@github.com:acme/api.git",
+				Group:     filepath.Join(dir, "work"),
+				Pinned:    true,
+				Aliases:   []string{"a"},
+				Protected: []string{"release"},
+			},
+			{URL: "/srv/git/tools.git", Path: "/opt/tools"},
+		},
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Fatalf("Load() = %+v, want %+v", m, expected)
+	}
+
+	dest, err := m.Repositories[0].Destination("/src", "{host}/{owner}/{repo}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "work", "github.com", "acme", "api"); dest != want {
+		t.Errorf("Destination() = %q, want %q", dest, want)
+	}
+	if dest, _ := m.Repositories[1].Destination("/src", "{host}/{owner}/{repo}"); dest != "/opt/tools" {
+		t.Errorf("Destination() = %q, want the repository's path", dest)
+	}
+}
+
+func TestLoadRejectsInvalidManifests(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "missing url",
+			content: "repositories:\n  - pinned: true\n",
+			wantErr: "has no url",
+		},
+		{
+			name:    "url without identifier or path",
+			content: "repositories:\n  - url: /srv/git/tools.git\n",
+			wantErr: "give it a path",
+		},
+		{
+			name:    "duplicate repository",
+			content: "repositories:\n  - url: git@github.com:acme/api.git\n  - url: https://github.com/acme/api\n",
+			wantErr: "listed twice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeManifest(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfine(t *testing.T) {
+	m := &Manifest{Repositories: []Repository{
+		{URL: "git@github.com:acme/api.git"},
+		{URL: "git@github.com:acme/web.git", Group: "/work/team"},
+		{URL: "git@github.com:acme/tools.git", Path: "/src/tools"},
+	}}
+	if err := m.Confine([]string{"/src", "/work"}); err != nil {
+		t.Errorf("Confine() = %v, want no error", err)
+	}
+
+	m.Repositories = append(m.Repositories,
+		Repository{URL: "git@evil.com:x/keys.git", Path: "/home/u/.ssh"},
+		Repository{URL: "git@evil.com:x/bin.git", Group: "/usr/local/bin"},
+	)
+	err := m.Confine([]string{"/src", "/work"})
+	if err == nil {
+		t.Fatal("Confine() = nil, want an error for the entries outside")
+	}
+	for _, want := range []string{"/home/u/.ssh", "/usr/local/bin"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Confine() = %v, want it to report %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Package parallel runs the same work over many items with bounded
+// concurrency.
+package parallel
+
+import "sync"
+
+// Map calls fn on every item, running at most n calls at once, and returns the
+// results in the order of items. n < 1 runs one call at a time.
+func Map[T, R any](items []T, n int, fn func(T) R) []R {
+	results := make([]R, len(items))
+	Each(items, n, func(i int, item T) {
+		results[i] = fn(item)
+	})
+	return results
+}
+
+// Each calls fn with every item and its index, running at most n calls at
+// once, and returns when all of them have. n < 1 runs one call at a time.
+func Each[T any](items []T, n int, fn func(i int, item T)) {
+	n = min(max(n, 1), len(items))
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i, items[i])
+			}
+		}()
+	}
+
+	for i := range items {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
@@ -0,0 +1,47 @@
+package parallel
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMap(t *testing.T) {
+	items := []int{5, 1, 4, 2, 3}
+
+	var running, peak atomic.Int32
+	results := Map(items, 2, func(n int) int {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		// Later items finish first, so results arrive out of order.
+		time.Sleep(time.Duration(n) * time.Millisecond)
+		return n * 10
+	})
+
+	if !slices.Equal(results, []int{50, 10, 40, 20, 30}) {
+		t.Errorf("Map() = %v, want results in the order of items", results)
+	}
+	if peak.Load() > 2 {
+		t.Errorf("Map() ran %d calls at once, want at most 2", peak.Load())
+	}
+
+	t.Run("empty input", func(t *testing.T) {
+		if results := Map(nil, 4, func(n int) int { return n }); len(results) != 0 {
+			t.Errorf("Map(nil) = %v, want empty", results)
+		}
+	})
+
+	t.Run("zero parallelism runs sequentially", func(t *testing.T) {
+		results := Map(items, 0, func(n int) int { return n })
+		if !slices.Equal(results, items) {
+			t.Errorf("Map() = %v, want %v", results, items)
+		}
+	})
+}
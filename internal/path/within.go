@@ -0,0 +1,13 @@
+package path
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// IsWithin reports whether path is dir or inside it. Both should be clean and
+// absolute; symlinks aren't resolved.
+func IsWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,22 @@
+package path
+
+import "testing"
+
+func TestIsWithin(t *testing.T) {
+	tests := []struct {
+		path, dir string
+		expected  bool
+	}{
+		{"/src/api", "/src/api", true},
+		{"/src/api/services", "/src/api", true},
+		{"/src/api-gateway", "/src/api", false},
+		{"/src", "/src/api", false},
+		{"/src/api/../x", "/src/api", false},
+		{"/src/..foo", "/src", true},
+	}
+	for _, tt := range tests {
+		if got := IsWithin(tt.path, tt.dir); got != tt.expected {
+			t.Errorf("IsWithin(%q, %q) = %v, want %v", tt.path, tt.dir, got, tt.expected)
+		}
+	}
+}
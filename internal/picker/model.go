@@ -0,0 +1,222 @@
+package picker
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// SGR sequences used when rendering.
+const (
+	styleReset    = "\x1b[0m"
+	styleSelected = "\x1b[7m"
+	styleFaint    = "\x1b[2m"
+	stylePrompt   = "\x1b[1m"
+)
+
+// key is a decoded keypress.
+type key int
+
+const (
+	keyNone key = iota
+	keyRune
+	keyEnter
+	keyCancel
+	keyUp
+	keyDown
+	keyBackspace
+	keyClearLine
+	keyDeleteWord
+)
+
+// model is the picker's state, kept separate from the terminal so it can be
+// driven and rendered in tests.
+type model struct {
+	prompt  string
+	items   []Item
+	filter  FilterFunc
+	query   string
+	matches []int // indexes into items, best match first
+	cursor  int   // position in matches
+	offset  int   // first visible position in matches
+	height  int   // maximum number of visible items
+	color   bool
+}
+
+func newModel(prompt, query string, items []Item, filter FilterFunc, height int, color bool) *model {
+	m := &model{prompt: prompt, items: items, filter: filter, query: query, height: max(height, 1), color: color}
+	m.refilter()
+	return m
+}
+
+// refilter recomputes the matches for the current query. Items keep their
+// original order within the same score, so the caller's ranking survives.
+func (m *model) refilter() {
+	scores := make(map[int]int, len(m.items))
+	m.matches = m.matches[:0]
+	for i := range m.items {
+		score := 1
+		if m.query != "" {
+			score = m.filter(m.query, i)
+		}
+		if score > 0 {
+			scores[i] = score
+			m.matches = append(m.matches, i)
+		}
+	}
+	slices.SortStableFunc(m.matches, func(a, b int) int {
+		return scores[b] - scores[a]
+	})
+	m.cursor, m.offset = 0, 0
+}
+
+// handle applies a keypress. It returns done once the user has chosen or
+// cancelled, with the chosen item's index or -1.
+func (m *model) handle(k key, r rune) (done bool, chosen int) {
+	switch k {
+	case keyEnter:
+		if len(m.matches) == 0 {
+			return false, -1
+		}
+		return true, m.matches[m.cursor]
+	case keyCancel:
+		return true, -1
+	case keyUp:
+		m.move(-1)
+	case keyDown:
+		m.move(1)
+	case keyRune:
+		m.query += string(r)
+		m.refilter()
+	case keyBackspace:
+		if m.query != "" {
+			_, size := utf8.DecodeLastRuneInString(m.query)
+			m.query = m.query[:len(m.query)-size]
+			m.refilter()
+		}
+	case keyClearLine:
+		m.query = ""
+		m.refilter()
+	case keyDeleteWord:
+		trimmed := strings.TrimRight(m.query, " /-_.")
+		m.query = trimmed[:strings.LastIndexAny(trimmed, " /-_.")+1]
+		m.refilter()
+	}
+	return false, -1
+}
+
+// move moves the cursor, wrapping around at either end, and scrolls to keep it
+// visible.
+func (m *model) move(delta int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.cursor = (m.cursor + delta + len(m.matches)) % len(m.matches)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+}
+
+// render draws the prompt line and the visible items, one line each, cut to
+// width. It returns the number of lines drawn below the prompt.
+func (m *model) render(w io.Writer, width int) int {
+	fmt.Fprintf(w, "%s\r\n", m.line(m.style(stylePrompt, m.prompt)+m.query+m.style(styleFaint, fmt.Sprintf("  %d/%d", len(m.matches), len(m.items))), width))
+
+	lines := 0
+	end := min(m.offset+m.height, len(m.matches))
+	for pos := m.offset; pos < end; pos++ {
+		item := m.items[m.matches[pos]]
+		text := "  " + item.Label
+		if item.Detail != "" {
+			text += "  " + m.style(styleFaint, item.Detail)
+		}
+		if pos == m.cursor {
+			text = m.style(styleSelected, "> "+ansi.Strip(text[2:])+" ")
+		}
+		fmt.Fprintf(w, "%s\r\n", m.line(text, width))
+		lines++
+	}
+	if len(m.matches) == 0 {
+		fmt.Fprintf(w, "%s\r\n", m.line(m.style(styleFaint, "  (no matches)"), width))
+		lines++
+	}
+	return lines
+}
+
+// line cuts s to the terminal width so it never wraps and throws off the
+// redraw.
+func (m *model) line(s string, width int) string {
+	if width > 0 && ansi.StringWidth(s) > width-1 {
+		s = ansi.Truncate(s, width-1, "…")
+		if m.color {
+			s += styleReset
+		}
+	}
+	return s
+}
+
+func (m *model) style(sgr, s string) string {
+	if !m.color {
+		return s
+	}
+	return sgr + s + styleReset
+}
+
+// decode splits raw terminal input into keypresses.
+func decode(input []byte) []keyPress {
+	presses := []keyPress{}
+	for len(input) > 0 {
+		switch {
+		case input[0] == 0x1b && len(input) >= 3 && (input[1] == '[' || input[1] == 'O'):
+			switch input[2] {
+			case 'A':
+				presses = append(presses, keyPress{key: keyUp})
+			case 'B':
+				presses = append(presses, keyPress{key: keyDown})
+			}
+			input = input[3:]
+		case input[0] == 0x1b:
+			presses = append(presses, keyPress{key: keyCancel})
+			input = input[1:]
+		case input[0] == '\r' || input[0] == '\n':
+			presses = append(presses, keyPress{key: keyEnter})
+			input = input[1:]
+		case input[0] == 0x03 || input[0] == 0x07: // ctrl-c, ctrl-g
+			presses = append(presses, keyPress{key: keyCancel})
+			input = input[1:]
+		case input[0] == 0x10 || input[0] == 0x0b: // ctrl-p, ctrl-k
+			presses = append(presses, keyPress{key: keyUp})
+			input = input[1:]
+		case input[0] == 0x0e || input[0] == '\t': // ctrl-n, tab
+			presses = append(presses, keyPress{key: keyDown})
+			input = input[1:]
+		case input[0] == 0x7f || input[0] == 0x08:
+			presses = append(presses, keyPress{key: keyBackspace})
+			input = input[1:]
+		case input[0] == 0x15: // ctrl-u
+			presses = append(presses, keyPress{key: keyClearLine})
+			input = input[1:]
+		case input[0] == 0x17: // ctrl-w
+			presses = append(presses, keyPress{key: keyDeleteWord})
+			input = input[1:]
+		case input[0] < 0x20:
+			input = input[1:]
+		default:
+			r, size := utf8.DecodeRune(input)
+			presses = append(presses, keyPress{key: keyRune, r: r})
+			input = input[size:]
+		}
+	}
+	return presses
+}
+
+type keyPress struct {
+	key key
+	r   rune
+}
@@ -0,0 +1,141 @@
+package picker
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func testModel(query string, height int) *model {
+	items := []Item{
+		{Label: "api", Detail: "~/src/acme/api"},
+		{Label: "api-gateway", Detail: "~/src/api-gateway"},
+		{Label: "legacy-api", Detail: "~/src/legacy-api"},
+		{Label: "billing", Detail: "~/src/billing"},
+	}
+	filter := func(query string, i int) int {
+		label := items[i].Label
+		switch {
+		case label == query:
+			return 3
+		case strings.HasPrefix(label, query):
+			return 2
+		case strings.Contains(label, query):
+			return 1
+		}
+		return 0
+	}
+	return newModel("> ", query, items, filter, height, false)
+}
+
+func labels(m *model) []string {
+	result := []string{}
+	for _, i := range m.matches {
+		result = append(result, m.items[i].Label)
+	}
+	return result
+}
+
+func TestModelFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{name: "empty query keeps order", query: "", expected: []string{"api", "api-gateway", "legacy-api", "billing"}},
+		{name: "best score first", query: "api", expected: []string{"api", "api-gateway", "legacy-api"}},
+		{name: "no matches", query: "xyz", expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testModel(tt.query, 10)
+			if got := labels(m); !slices.Equal(got, tt.expected) {
+				t.Errorf("matches = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestModelKeys(t *testing.T) {
+	t.Run("typing narrows and backspace widens", func(t *testing.T) {
+		m := testModel("", 10)
+		for _, press := range decode([]byte("bil")) {
+			m.handle(press.key, press.r)
+		}
+		if got := labels(m); !slices.Equal(got, []string{"billing"}) {
+			t.Errorf("matches = %v, want [billing]", got)
+		}
+
+		for _, press := range decode([]byte{0x7f, 0x7f, 0x7f}) {
+			m.handle(press.key, press.r)
+		}
+		if m.query != "" || len(m.matches) != 4 {
+			t.Errorf("query = %q with %d matches, want everything back", m.query, len(m.matches))
+		}
+	})
+
+	t.Run("arrows move and wrap", func(t *testing.T) {
+		m := testModel("api", 10)
+		m.handle(keyDown, 0)
+		if done, chosen := m.handle(keyEnter, 0); !done || m.items[chosen].Label != "api-gateway" {
+			t.Errorf("handle(enter) = (%v, %d), want api-gateway", done, chosen)
+		}
+
+		m.handle(keyUp, 0)
+		m.handle(keyUp, 0)
+		if _, chosen := m.handle(keyEnter, 0); m.items[chosen].Label != "legacy-api" {
+			t.Errorf("Expected moving up past the top to wrap to legacy-api, got %q", m.items[chosen].Label)
+		}
+	})
+
+	t.Run("escape cancels", func(t *testing.T) {
+		m := testModel("", 10)
+		presses := decode([]byte{0x1b})
+		if len(presses) != 1 {
+			t.Fatalf("decode(esc) = %v, want one keypress", presses)
+		}
+		if done, chosen := m.handle(presses[0].key, presses[0].r); !done || chosen != -1 {
+			t.Errorf("handle(esc) = (%v, %d), want cancelled", done, chosen)
+		}
+	})
+
+	t.Run("enter without matches does nothing", func(t *testing.T) {
+		m := testModel("xyz", 10)
+		if done, _ := m.handle(keyEnter, 0); done {
+			t.Error("Expected enter with no matches to keep the picker open")
+		}
+	})
+
+	t.Run("ctrl-w deletes a word", func(t *testing.T) {
+		m := testModel("acme/api", 10)
+		m.handle(keyDeleteWord, 0)
+		if m.query != "acme/" {
+			t.Errorf("query = %q, want %q", m.query, "acme/")
+		}
+	})
+}
+
+func TestModelScrolls(t *testing.T) {
+	m := testModel("", 2)
+	for range 3 {
+		m.handle(keyDown, 0)
+	}
+
+	var out strings.Builder
+	lines := m.render(&out, 80)
+	if lines != 2 {
+		t.Errorf("render() drew %d items, want 2", lines)
+	}
+	if !strings.Contains(out.String(), "> billing") || strings.Contains(out.String(), "api-gateway") {
+		t.Errorf("Expected the view to scroll to billing, got:\n%s", out.String())
+	}
+}
+
+func TestDecodeArrows(t *testing.T) {
+	presses := decode([]byte("\x1b[A\x1b[Bx"))
+	expected := []keyPress{{key: keyUp}, {key: keyDown}, {key: keyRune, r: 'x'}}
+	if !slices.Equal(presses, expected) {
+		t.Errorf("decode() = %v, want %v", presses, expected)
+	}
+}
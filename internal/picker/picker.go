@@ -0,0 +1,94 @@
+// Package picker is a small interactive list for choosing between candidates
+// on the terminal. It draws on and reads from the controlling terminal
+// directly, so stdout stays free for the shell protocol.
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// maxHeight caps the number of candidates shown at once.
+const maxHeight = 10
+
+var (
+	ErrCancelled  = errors.New("selection cancelled")
+	ErrNoTerminal = errors.New("no terminal available")
+)
+
+// Item is a candidate in the list.
+type Item struct {
+	Label  string // shown first, e.g. the repository name
+	Detail string // shown dimmed after the label, e.g. branch and path
+}
+
+// FilterFunc scores how well the typed query matches the item at index i.
+// Items scoring 0 are hidden; higher scores are listed first.
+type FilterFunc func(query string, i int) int
+
+// Available reports whether a terminal is there to show the picker on.
+func Available() bool {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+	defer tty.Close()
+	return term.IsTerminal(tty.Fd())
+}
+
+// Pick lets the user choose one of items, starting with query already typed.
+// It returns the index of the chosen item.
+func Pick(prompt, query string, items []Item, filter FilterFunc) (int, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return -1, ErrNoTerminal
+	}
+	defer tty.Close()
+
+	fd := tty.Fd()
+	if !term.IsTerminal(fd) {
+		return -1, ErrNoTerminal
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return -1, err
+	}
+	defer term.Restore(fd, state)
+
+	width, height, err := term.GetSize(fd)
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = 80, maxHeight+1
+	}
+	_, noColor := os.LookupEnv("NO_COLOR")
+	m := newModel(prompt, query, items, filter, min(maxHeight, height-1), !noColor)
+
+	// Hide the cursor while drawing, and clear the list on the way out.
+	fmt.Fprint(tty, "\x1b[?25l")
+	defer fmt.Fprint(tty, "\r\x1b[J\x1b[?25h")
+
+	buf := make([]byte, 64)
+	for {
+		var frame strings.Builder
+		lines := m.render(&frame, width)
+		// Draw, then move back up to the prompt line for the next redraw.
+		fmt.Fprintf(tty, "\r\x1b[J%s\x1b[%dA", frame.String(), lines+1)
+
+		n, err := tty.Read(buf)
+		if err != nil {
+			return -1, err
+		}
+		for _, press := range decode(buf[:n]) {
+			if done, chosen := m.handle(press.key, press.r); done {
+				if chosen < 0 {
+					return -1, ErrCancelled
+				}
+				return chosen, nil
+			}
+		}
+	}
+}
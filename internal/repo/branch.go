@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"fmt"
 	"os/exec"
 	"strings"
 )
@@ -44,30 +45,8 @@ func (r *Repo) IsStale(branch string) bool {
 
 // HasWorktree checks if a branch has an active worktree.
 func (r *Repo) HasWorktree(branch string) bool {
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = r.Path
-
-	output, err := cmd.Output()
-	if err != nil {
-		return false
-	}
-
-	// Parse porcelain output - each worktree has:
-	// worktree /path/to/worktree
-	// HEAD <commit>
-	// branch refs/heads/<branch>
-	// (blank line)
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, "branch refs/heads/") {
-			wtBranch := strings.TrimPrefix(line, "branch refs/heads/")
-			if wtBranch == branch {
-				return true
-			}
-		}
-	}
-
-	return false
+	_, ok := r.WorktreeForBranch(branch)
+	return ok
 }
 
 // ListBranches returns all local branch names in the repository.
@@ -96,3 +75,34 @@ func (r *Repo) DeleteBranch(branch string) error {
 	cmd.Dir = r.Path
 	return cmd.Run()
 }
+
+// Checkout switches the working tree to branch. A branch that only exists on
+// origin is created to track it.
+func (r *Repo) Checkout(branch string) error {
+	return r.git("checkout", branch)
+}
+
+// CheckoutCommit checks out commit: on branch if branch points at it, detached
+// otherwise. A commit the repository doesn't have is fetched first. Reports
+// whether HEAD ended up detached.
+func (r *Repo) CheckoutCommit(branch, commit string) (bool, error) {
+	if !r.hasCommit(commit) {
+		if err := r.git("fetch", "--all", "--quiet"); err != nil {
+			return false, err
+		}
+		if !r.hasCommit(commit) {
+			return false, fmt.Errorf("commit %s is in neither the repository nor its remotes", commit)
+		}
+	}
+
+	if branch != "" {
+		if tip, err := r.output("rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err == nil && strings.TrimSpace(tip) == commit {
+			return false, r.git("checkout", "--quiet", branch)
+		}
+	}
+	return true, r.git("checkout", "--quiet", "--detach", commit)
+}
+
+func (r *Repo) hasCommit(commit string) bool {
+	return r.git("cat-file", "-e", commit+"^{commit}") == nil
+}
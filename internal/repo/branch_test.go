@@ -3,6 +3,7 @@ package repo
 import (
 	"os"
 	"os/exec"
+	"strings"
 	"testing"
 
 	"github.com/go-git/go-git/v5"
@@ -253,3 +254,40 @@ func TestHasWorktree(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckoutCommit(t *testing.T) {
+	repo, tmpDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	first, err := repo.output("rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first = strings.TrimSpace(first)
+	commitFile(t, tmpDir, "second.txt", "second")
+
+	// master has moved on, so its old commit is checked out detached
+	detached, err := repo.CheckoutCommit("master", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, _ := repo.Status()
+	if !detached || status.Branch != "" || status.Head != first {
+		t.Errorf("CheckoutCommit(master, first) = detached %v at %q on %q, want detached at %q", detached, status.Head, status.Branch, first)
+	}
+
+	// A branch at the commit is checked out by name
+	runGit(t, tmpDir, "branch", "old", first)
+	detached, err = repo.CheckoutCommit("old", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, _ = repo.Status()
+	if detached || status.Branch != "old" {
+		t.Errorf("CheckoutCommit(old, first) = detached %v on %q, want branch old", detached, status.Branch)
+	}
+
+	if _, err := repo.CheckoutCommit("", strings.Repeat("1", 40)); err == nil {
+		t.Error("CheckoutCommit() of an unknown commit should fail")
+	}
+}
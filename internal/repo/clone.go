@@ -0,0 +1,80 @@
+package repo
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kernelle-soft/gimme/internal/path"
+)
+
+// ClonePath returns where a clone of the repository with identifier, as
+// returned by NormalizeRemoteURL, should go according to layout. Layouts may
+// use {host}, {owner}, {repo} and {identifier}; relative layouts are resolved
+// against root. Owners nested in groups, e.g. gitlab.com/group/sub/repo, keep
+// their slashes, and user info and ports are dropped from the host.
+//
+// Identifiers come from remote URLs that may not be the user's, so one with
+// an empty, "." or ".." segment is rejected, as is a relative layout that
+// would leave root.
+func ClonePath(identifier, root, layout string) (string, error) {
+	segments := strings.Split(strings.Trim(identifier, "/"), "/")
+	if len(segments) < 3 {
+		return "", fmt.Errorf("%q is not of the form host/owner/repo", identifier)
+	}
+
+	host := segments[0]
+	if at := strings.LastIndex(host, "@"); at >= 0 {
+		host = host[at+1:]
+	}
+	host, _, _ = strings.Cut(host, ":")
+	segments[0] = host
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("%q is not of the form host/owner/repo", identifier)
+		}
+	}
+
+	// Expand the layout before filling it in, so the identifier can't name
+	// environment variables
+	layout, err := path.Normalize(layout)
+	if err != nil {
+		return "", err
+	}
+	replacer := strings.NewReplacer(
+		"{host}", host,
+		"{owner}", strings.Join(segments[1:len(segments)-1], "/"),
+		"{repo}", segments[len(segments)-1],
+		"{identifier}", strings.Join(segments, "/"),
+	)
+	expanded := filepath.Clean(replacer.Replace(layout))
+
+	if !filepath.IsAbs(expanded) {
+		expanded = filepath.Join(root, expanded)
+		if !path.IsWithin(expanded, root) {
+			return "", fmt.Errorf("clone layout %q puts %q outside %q", layout, identifier, root)
+		}
+	}
+	return expanded, nil
+}
+
+// Clone clones url into dest, creating missing parent directories, and opens
+// the new repository. dest must not exist or be an empty directory.
+func Clone(url, dest string) (Repo, error) {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return Repo{}, err
+	}
+
+	cmd := exec.Command("git", "clone", "--quiet", url, dest)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return Repo{}, commandError(err, output)
+	}
+
+	gitRepo, err := Open(dest)
+	if err != nil {
+		return Repo{}, err
+	}
+	return NewRepo(gitRepo, dest, filepath.Base(dest)), nil
+}
@@ -0,0 +1,135 @@
+package repo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClonePath(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier string
+		layout     string
+		expected   string
+		wantErr    bool
+	}{
+		{
+			name:       "default layout",
+			identifier: "github.com/acme/api",
+			layout:     "{host}/{owner}/{repo}",
+			expected:   "/src/github.com/acme/api",
+		},
+		{
+			name:       "nested owner keeps its slashes",
+			identifier: "gitlab.com/acme/platform/api",
+			layout:     "{host}/{owner}/{repo}",
+			expected:   "/src/gitlab.com/acme/platform/api",
+		},
+		{
+			name:       "port is dropped from host",
+			identifier: "git.example.com:2222/acme/api",
+			layout:     "{identifier}",
+			expected:   "/src/git.example.com/acme/api",
+		},
+		{
+			name:       "user info is dropped from host",
+			identifier: "user@github.com/acme/api",
+			layout:     "{host}/{owner}/{repo}",
+			expected:   "/src/github.com/acme/api",
+		},
+		{
+			name:       "user info and port are dropped from identifier",
+			identifier: "user:token@git.example.com:8443/acme/api",
+			layout:     "{identifier}",
+			expected:   "/src/git.example.com/acme/api",
+		},
+		{
+			name:       "flat layout",
+			identifier: "github.com/acme/api",
+			layout:     "{owner}-{repo}",
+			expected:   "/src/acme-api",
+		},
+		{
+			name:       "absolute layout ignores root",
+			identifier: "github.com/acme/api",
+			layout:     "/work/{repo}",
+			expected:   "/work/api",
+		},
+		{
+			name:       "parent segments are rejected",
+			identifier: NormalizeRemoteURL("git@evil.com:../../../../home/u/.ssh"),
+			layout:     "{host}/{owner}/{repo}",
+			wantErr:    true,
+		},
+		{
+			name:       "parent repo is rejected",
+			identifier: "evil.com/acme/..",
+			layout:     "{repo}",
+			wantErr:    true,
+		},
+		{
+			name:       "empty segments are rejected",
+			identifier: "evil.com/acme//api",
+			layout:     "{identifier}",
+			wantErr:    true,
+		},
+		{
+			name:       "relative layout can't leave root",
+			identifier: "github.com/acme/api",
+			layout:     "../{repo}",
+			wantErr:    true,
+		},
+		{
+			name:       "identifier can't name environment variables",
+			identifier: "github.com/acme/$HOME",
+			layout:     "{repo}",
+			expected:   "/src/$HOME",
+		},
+		{
+			name:       "identifier without owner",
+			identifier: "github.com/api",
+			layout:     "{host}/{owner}/{repo}",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ClonePath(tt.identifier, "/src", tt.layout)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ClonePath(%q) = %q, want an error", tt.identifier, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("ClonePath(%q, %q) = %q, want %q", tt.identifier, tt.layout, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	source, tmpDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	dest := filepath.Join(tmpDir, "clones", "github.com", "acme", "api")
+	cloned, err := Clone(source.Path, dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cloned.Path != dest || cloned.Name != "api" {
+		t.Errorf("Clone() = {Path: %q, Name: %q}, want {Path: %q, Name: \"api\"}", cloned.Path, cloned.Name, dest)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "test.txt")); err != nil {
+		t.Errorf("clone has no checkout: %v", err)
+	}
+
+	if _, err := Clone(source.Path, dest); err == nil {
+		t.Error("Clone() into an existing repository should fail")
+	}
+}
@@ -0,0 +1,101 @@
+package repo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotRepository is returned when no git repository encloses a path.
+var ErrNotRepository = errors.New("not in a git repository")
+
+// Location describes the git repository enclosing a path.
+type Location struct {
+	WorktreeRoot string // top of the working tree containing the path
+	GitDir       string // git directory of that working tree
+	CommonDir    string // git directory shared by all of the repository's worktrees
+	MainRoot     string // working tree of the main repository, "" if it is bare
+	Linked       bool   // WorktreeRoot is a linked worktree (git worktree add)
+}
+
+// Locate walks up from path to the nearest enclosing working tree. It
+// understands .git directories as well as the .git files used by linked
+// worktrees and submodules, and resolves a linked worktree back to its main
+// repository. Submodules are repositories in their own right, so they resolve
+// to themselves.
+func Locate(path string) (*Location, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		info, err := os.Stat(dotGit)
+		if err == nil {
+			if info.IsDir() {
+				return &Location{WorktreeRoot: dir, GitDir: dotGit, CommonDir: dotGit, MainRoot: dir}, nil
+			}
+			return locateFromGitFile(dir, dotGit)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, ErrNotRepository
+		}
+		dir = parent
+	}
+}
+
+// locateFromGitFile resolves a working tree whose .git is a "gitdir: <path>"
+// file rather than a directory.
+func locateFromGitFile(root, dotGit string) (*Location, error) {
+	gitDir, err := readPointer(dotGit, "gitdir: ")
+	if err != nil {
+		return nil, err
+	}
+
+	loc := &Location{WorktreeRoot: root, GitDir: gitDir, CommonDir: gitDir, MainRoot: root}
+
+	// Linked worktrees point back to the main repository's git directory
+	// through a commondir file. Submodules don't have one.
+	commonDir, err := readPointer(filepath.Join(gitDir, "commondir"), "")
+	if errors.Is(err, os.ErrNotExist) {
+		return loc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	loc.Linked = true
+	loc.CommonDir = commonDir
+	loc.MainRoot = ""
+	if filepath.Base(commonDir) == ".git" {
+		loc.MainRoot = filepath.Dir(commonDir)
+	}
+	return loc, nil
+}
+
+// readPointer reads a path from a git pointer file, resolving it relative to
+// the file's directory.
+func readPointer(file, prefix string) (string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	content := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(content, prefix) {
+		return "", errors.New("unrecognized format in " + file)
+	}
+
+	target := strings.TrimPrefix(content, prefix)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(file), target)
+	}
+	return filepath.Clean(target), nil
+}
@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocate(t *testing.T) {
+	repo, tmpDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	root, err := filepath.EvalSymlinks(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("from the root", func(t *testing.T) {
+		loc, err := Locate(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loc.WorktreeRoot != root || loc.MainRoot != root || loc.Linked {
+			t.Errorf("Locate() = %+v, want root %q and not linked", loc, root)
+		}
+	})
+
+	t.Run("from a subdirectory", func(t *testing.T) {
+		loc, err := Locate(sub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loc.WorktreeRoot != root {
+			t.Errorf("WorktreeRoot = %q, want %q", loc.WorktreeRoot, root)
+		}
+	})
+
+	t.Run("outside any repository", func(t *testing.T) {
+		outside, err := os.MkdirTemp("", "gimme-not-a-repo-*")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(outside)
+
+		if _, err := Locate(outside); err != ErrNotRepository {
+			t.Errorf("Locate() error = %v, want ErrNotRepository", err)
+		}
+	})
+
+	t.Run("sibling with a shared prefix", func(t *testing.T) {
+		sibling := root + "bar"
+		if err := os.MkdirAll(sibling, 0755); err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(sibling)
+
+		if loc, err := Locate(sibling); err == nil && loc.WorktreeRoot == root {
+			t.Errorf("Locate(%q) resolved to %q, a different directory", sibling, root)
+		}
+	})
+
+	t.Run("linked worktree resolves to the main repository", func(t *testing.T) {
+		wtParent, err := os.MkdirTemp("", "gimme-worktree-*")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(wtParent)
+		wtDir := filepath.Join(wtParent, "feature")
+
+		cmd := exec.Command("git", "worktree", "add", "-b", "feature", wtDir)
+		cmd.Dir = repo.Path
+		if err := cmd.Run(); err != nil {
+			t.Fatal(err)
+		}
+		wtDir, _ = filepath.EvalSymlinks(wtDir)
+
+		loc, err := Locate(wtDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !loc.Linked {
+			t.Error("Expected worktree to be linked")
+		}
+		if loc.WorktreeRoot != wtDir {
+			t.Errorf("WorktreeRoot = %q, want %q", loc.WorktreeRoot, wtDir)
+		}
+		if loc.MainRoot != root {
+			t.Errorf("MainRoot = %q, want %q", loc.MainRoot, root)
+		}
+		if loc.CommonDir != filepath.Join(root, ".git") {
+			t.Errorf("CommonDir = %q, want %q", loc.CommonDir, filepath.Join(root, ".git"))
+		}
+	})
+
+	t.Run("submodule resolves to itself", func(t *testing.T) {
+		cmd := exec.Command("git", "-c", "protocol.file.allow=always", "submodule", "add", root, "mods/self")
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git submodule add: %v\n%s", err, out)
+		}
+
+		subRoot := filepath.Join(root, "mods", "self")
+		loc, err := Locate(filepath.Join(subRoot, "a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loc.Linked {
+			t.Error("Expected submodule not to be treated as a linked worktree")
+		}
+		if loc.WorktreeRoot != subRoot || loc.MainRoot != subRoot {
+			t.Errorf("Locate() = %+v, want submodule root %q", loc, subRoot)
+		}
+		if loc.GitDir != filepath.Join(root, ".git", "modules", "mods", "self") {
+			t.Errorf("GitDir = %q, want it under the superproject's modules", loc.GitDir)
+		}
+	})
+}
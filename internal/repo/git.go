@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// Open opens the repository at path. Linked worktrees are opened with their
+// shared git directory, so remotes and branches are visible from them.
+func Open(path string) (*git.Repository, error) {
+	return git.PlainOpenWithOptions(path, &git.PlainOpenOptions{
+		EnableDotGitCommonDir: true,
+	})
+}
+
+// git runs a git command in the repository, including git's own message in
+// the error when it fails.
+func (r *Repo) git(args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Path
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return commandError(err, output)
+	}
+	return nil
+}
+
+// output runs a git command in the repository and returns its stdout, with
+// git's message in the error when it fails.
+func (r *Repo) output(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Path
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", commandError(err, stderr.Bytes())
+	}
+	return string(output), nil
+}
+
+// commandError wraps a failed command's error with the last line of its
+// output, which is where git explains what went wrong.
+func commandError(err error, output []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	message := strings.TrimSpace(lines[len(lines)-1])
+	if message == "" {
+		return err
+	}
+	return errors.New(message)
+}
@@ -0,0 +1,62 @@
+package repo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// LocalConfigFile is the name of the config file a repository can commit at
+// its root to tell gimme about itself.
+const LocalConfigFile = ".gimme.yaml"
+
+// LocalConfig is a repository's own .gimme.yaml:
+//
+//	protected: [develop]       (branches clean never deletes, like the global protected branches)
+//	entry: services/api        (subdirectory to land in when jumping to the repo)
+//	tags: [backend]
+//	on-enter: nvm use          (run by the shell after jumping to the repo)
+//	env:                       (set by the shell after jumping to the repo)
+//	  GOFLAGS: -mod=mod
+//	tasks:                     (run with 'gimme task <name>')
+//	  test: go test ./...
+//
+// The shell only runs on-enter and sets env once the user has trusted the
+// file with 'gimme trust'.
+type LocalConfig struct {
+	Protected []string          `yaml:"protected"`
+	Entry     string            `yaml:"entry"`
+	Tags      []string          `yaml:"tags"`
+	OnEnter   string            `yaml:"on-enter"`
+	Env       map[string]string `yaml:"env"`
+	Tasks     map[string]string `yaml:"tasks"`
+
+	Hash string `yaml:"-"` // SHA-256 of the file, "" when there is none
+}
+
+// LoadLocalConfig reads the .gimme.yaml at the root of a working tree. A
+// missing file is an empty config.
+func LoadLocalConfig(root string) (LocalConfig, error) {
+	data, err := os.ReadFile(filepath.Join(root, LocalConfigFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return LocalConfig{}, nil
+	}
+	if err != nil {
+		return LocalConfig{}, err
+	}
+
+	// Read with yaml rather than viper, which would lowercase environment
+	// variable and task names
+	var local LocalConfig
+	if err := yaml.Unmarshal(data, &local); err != nil {
+		return LocalConfig{}, fmt.Errorf("%s: %w", LocalConfigFile, err)
+	}
+	sum := sha256.Sum256(data)
+	local.Hash = hex.EncodeToString(sum[:])
+	return local, nil
+}
@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadLocalConfig(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-local-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	local, err := LoadLocalConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("missing file: unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(local, LocalConfig{}) {
+		t.Errorf("missing file: got %+v, want an empty config", local)
+	}
+
+	data := `protected: [develop]
+entry: services/api
+tags: [backend]
+on-enter: nvm use
+env:
+  GOFLAGS: -mod=mod
+tasks:
+  testAll: go test ./...
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, LocalConfigFile), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	local, err = LoadLocalConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := LocalConfig{
+		Protected: []string{"develop"},
+		Entry:     "services/api",
+		Tags:      []string{"backend"},
+		OnEnter:   "nvm use",
+		Env:       map[string]string{"GOFLAGS": "-mod=mod"},
+		Tasks:     map[string]string{"testAll": "go test ./..."},
+	}
+	if len(local.Hash) != 64 {
+		t.Errorf("Hash = %q, want a SHA-256", local.Hash)
+	}
+	local.Hash = ""
+	if !reflect.DeepEqual(local, expected) {
+		t.Errorf("got %+v, want %+v", local, expected)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, LocalConfigFile), []byte("tags: [unclosed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLocalConfig(tmpDir); err == nil {
+		t.Error("invalid yaml: expected an error")
+	}
+}
@@ -0,0 +1,195 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrDirty      = errors.New("working tree has local changes")
+	ErrDetached   = errors.New("HEAD is detached")
+	ErrNoUpstream = errors.New("branch has no upstream")
+	ErrDiverged   = errors.New("branch has diverged from its upstream")
+	ErrConflict   = errors.New("rebase onto upstream conflicts")
+)
+
+// FetchResult is what a fetch changed in the remote-tracking branches.
+type FetchResult struct {
+	Updated []string // remote-tracking branches that are new or moved
+	Pruned  []string // remote-tracking branches deleted because the remote deleted them
+}
+
+// Fetch fetches every remote. With prune, remote-tracking branches whose
+// branch is gone from the remote are deleted.
+func (r *Repo) Fetch(prune bool) (FetchResult, error) {
+	before, err := r.remoteRefs()
+	if err != nil {
+		return FetchResult{}, err
+	}
+
+	args := []string{"fetch", "--all", "--quiet"}
+	if prune {
+		args = append(args, "--prune")
+	}
+	if err := r.git(args...); err != nil {
+		return FetchResult{}, err
+	}
+
+	after, err := r.remoteRefs()
+	if err != nil {
+		return FetchResult{}, err
+	}
+
+	result := FetchResult{Updated: []string{}, Pruned: []string{}}
+	for ref, commit := range after {
+		if before[ref] != commit {
+			result.Updated = append(result.Updated, ref)
+		}
+	}
+	for ref := range before {
+		if _, ok := after[ref]; !ok {
+			result.Pruned = append(result.Pruned, ref)
+		}
+	}
+	return result, nil
+}
+
+// remoteRefs maps each remote-tracking branch, e.g. "origin/main", to the
+// commit it points at.
+func (r *Repo) remoteRefs() (map[string]string, error) {
+	output, err := r.output("for-each-ref", "--format=%(objectname) %(refname:short)", "refs/remotes")
+	if err != nil {
+		return nil, err
+	}
+
+	refs := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if commit, ref, ok := strings.Cut(line, " "); ok && !strings.HasSuffix(ref, "/HEAD") {
+			refs[ref] = commit
+		}
+	}
+	return refs, nil
+}
+
+// PullResult is how a pull moved the current branch.
+type PullResult struct {
+	From    string // commit before the pull
+	To      string // commit after it; the same as From when already up to date
+	Commits int    // commits taken from upstream
+	Rebased bool   // local commits were rebased onto upstream
+}
+
+// Pull brings the current branch up to date with its upstream: fetch, then
+// fast-forward. A branch with commits of its own has diverged, which is
+// ErrDiverged unless rebase is set, in which case they're rebased onto
+// upstream. A rebase that conflicts is aborted, leaving the branch as it was,
+// and returns ErrConflict.
+//
+// Pull refuses to touch a working tree with local changes or an operation in
+// progress (ErrDirty), a detached HEAD (ErrDetached) or a branch without an
+// upstream (ErrNoUpstream).
+func (r *Repo) Pull(rebase bool) (PullResult, error) {
+	status, err := r.Status()
+	switch {
+	case err != nil:
+		return PullResult{}, err
+	case status.IsDirty() || status.Operation != "":
+		return PullResult{}, ErrDirty
+	case status.Branch == "":
+		return PullResult{}, ErrDetached
+	case status.Upstream == "":
+		return PullResult{}, ErrNoUpstream
+	}
+
+	if err := r.git("fetch", "--quiet"); err != nil {
+		return PullResult{}, err
+	}
+	if status, err = r.Status(); err != nil {
+		return PullResult{}, err
+	}
+
+	result := PullResult{From: status.Head, To: status.Head, Commits: status.Behind}
+	switch {
+	case status.Behind == 0:
+		return result, nil
+	case status.Ahead > 0 && !rebase:
+		return result, fmt.Errorf("%w (%d ahead, %d behind)", ErrDiverged, status.Ahead, status.Behind)
+	case status.Ahead > 0:
+		if err := r.git("rebase", "--quiet", "@{upstream}"); err != nil {
+			r.git("rebase", "--abort")
+			return result, ErrConflict
+		}
+		result.Rebased = true
+	default:
+		if err := r.git("merge", "--ff-only", "--quiet", "@{upstream}"); err != nil {
+			return result, err
+		}
+	}
+
+	head, err := r.output("rev-parse", "HEAD")
+	if err != nil {
+		return result, err
+	}
+	result.To = strings.TrimSpace(head)
+	return result, nil
+}
+
+// FastForward moves a local branch to its upstream when that's a
+// fast-forward, without checking it out. A branch checked out in one of the
+// repository's worktrees is merged there instead, so its files follow, unless
+// that worktree has local changes (ErrDirty). Branches with commits their
+// upstream doesn't have are ErrDiverged; ones without an upstream are
+// ErrNoUpstream.
+func (r *Repo) FastForward(branch string) (PullResult, error) {
+	upstream, err := r.output("rev-parse", "--abbrev-ref", branch+"@{upstream}")
+	if err != nil {
+		return PullResult{}, ErrNoUpstream
+	}
+	upstream = strings.TrimSpace(upstream)
+
+	from, err := r.output("rev-parse", "refs/heads/"+branch)
+	if err != nil {
+		return PullResult{}, err
+	}
+	to, err := r.output("rev-parse", upstream)
+	if err != nil {
+		return PullResult{}, err
+	}
+	result := PullResult{From: strings.TrimSpace(from), To: strings.TrimSpace(from)}
+	to = strings.TrimSpace(to)
+
+	// Already there, or only ahead: nothing to take
+	if result.From == to || r.isMergedInto(to, result.From) {
+		return result, nil
+	}
+	if !r.isMergedInto(result.From, to) {
+		return result, ErrDiverged
+	}
+
+	count, err := r.output("rev-list", "--count", result.From+".."+to)
+	if err != nil {
+		return result, err
+	}
+	result.Commits, _ = strconv.Atoi(strings.TrimSpace(count))
+
+	if wt, ok := r.WorktreeForBranch(branch); ok {
+		checkedOut := &Repo{Path: wt.Path, Name: r.Name}
+		status, err := checkedOut.Status()
+		if err != nil {
+			return result, err
+		}
+		if status.IsDirty() || status.Operation != "" {
+			return result, ErrDirty
+		}
+		if err := checkedOut.git("merge", "--ff-only", "--quiet", to); err != nil {
+			return result, err
+		}
+	} else if err := r.git("update-ref", "-m", "gimme sync: fast-forward", "refs/heads/"+branch, to, result.From); err != nil {
+		return result, err
+	}
+
+	result.To = to
+	return result, nil
+}
@@ -0,0 +1,263 @@
+package repo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// setupRemote gives the test repo an origin in a bare repository next to it,
+// with master tracking origin/master, and returns a second clone of origin
+// for pushing changes from "elsewhere".
+func setupRemote(t *testing.T, tmpDir string) string {
+	t.Helper()
+
+	remoteDir, err := os.MkdirTemp("", "gimme-remote-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(remoteDir) })
+
+	bare := filepath.Join(remoteDir, "origin.git")
+	runGit(t, remoteDir, "clone", "--quiet", "--bare", tmpDir, bare)
+	runGit(t, tmpDir, "remote", "add", "origin", bare)
+	runGit(t, tmpDir, "fetch", "--quiet", "origin")
+	runGit(t, tmpDir, "branch", "--quiet", "--set-upstream-to", "origin/master")
+
+	other := filepath.Join(remoteDir, "other")
+	runGit(t, remoteDir, "clone", "--quiet", bare, other)
+	return other
+}
+
+// commitFile commits content to name in dir.
+func commitFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "--quiet", "-m", "change "+name)
+}
+
+func TestFetch(t *testing.T) {
+	repo, tmpDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+	other := setupRemote(t, tmpDir)
+
+	result, err := repo.Fetch(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Updated) != 0 || len(result.Pruned) != 0 {
+		t.Errorf("Fetch() = %+v, want nothing changed", result)
+	}
+
+	commitFile(t, other, "remote.txt", "remote")
+	runGit(t, other, "push", "--quiet", "origin", "master", "master:feature")
+
+	result, err = repo.Fetch(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(result.Updated)
+	if !slices.Equal(result.Updated, []string{"origin/feature", "origin/master"}) {
+		t.Errorf("Fetch().Updated = %v, want origin/feature and origin/master", result.Updated)
+	}
+
+	runGit(t, other, "push", "--quiet", "origin", "--delete", "feature")
+
+	t.Run("without prune", func(t *testing.T) {
+		result, err := repo.Fetch(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Pruned) != 0 {
+			t.Errorf("Fetch().Pruned = %v, want nothing pruned", result.Pruned)
+		}
+	})
+
+	t.Run("with prune", func(t *testing.T) {
+		result, err := repo.Fetch(true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(result.Pruned, []string{"origin/feature"}) {
+			t.Errorf("Fetch().Pruned = %v, want origin/feature", result.Pruned)
+		}
+	})
+}
+
+func TestPull(t *testing.T) {
+	repo, tmpDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+	other := setupRemote(t, tmpDir)
+
+	t.Run("up to date", func(t *testing.T) {
+		result, err := repo.Pull(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.From != result.To || result.Commits != 0 {
+			t.Errorf("Pull() = %+v, want nothing to do", result)
+		}
+	})
+
+	t.Run("fast-forward", func(t *testing.T) {
+		commitFile(t, other, "one.txt", "one")
+		commitFile(t, other, "two.txt", "two")
+		runGit(t, other, "push", "--quiet", "origin", "master")
+
+		result, err := repo.Pull(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.From == result.To || result.Commits != 2 || result.Rebased {
+			t.Errorf("Pull() = %+v, want a fast-forward by 2 commits", result)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "two.txt")); err != nil {
+			t.Error("Expected the pulled file in the working tree")
+		}
+	})
+
+	t.Run("dirty working tree", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.txt"), []byte("local edit"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		defer runGit(t, tmpDir, "checkout", "--", "test.txt")
+
+		if _, err := repo.Pull(false); !errors.Is(err, ErrDirty) {
+			t.Errorf("Pull() error = %v, want ErrDirty", err)
+		}
+	})
+
+	t.Run("diverged", func(t *testing.T) {
+		commitFile(t, other, "remote.txt", "remote")
+		runGit(t, other, "push", "--quiet", "origin", "master")
+		commitFile(t, tmpDir, "local.txt", "local")
+
+		if _, err := repo.Pull(false); !errors.Is(err, ErrDiverged) {
+			t.Fatalf("Pull() error = %v, want ErrDiverged", err)
+		}
+
+		result, err := repo.Pull(true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Rebased || result.Commits != 1 {
+			t.Errorf("Pull(rebase) = %+v, want 1 commit taken and a rebase", result)
+		}
+		status, _ := repo.Status()
+		if status.Ahead != 1 || status.Behind != 0 {
+			t.Errorf("Status() after rebase = %+v, want 1 ahead, 0 behind", status)
+		}
+	})
+
+	t.Run("conflicting rebase is aborted", func(t *testing.T) {
+		commitFile(t, other, "test.txt", "remote version")
+		runGit(t, other, "push", "--quiet", "origin", "master")
+		commitFile(t, tmpDir, "test.txt", "local version")
+
+		if _, err := repo.Pull(true); !errors.Is(err, ErrConflict) {
+			t.Fatalf("Pull(rebase) error = %v, want ErrConflict", err)
+		}
+		status, _ := repo.Status()
+		if status.Operation != "" || status.IsDirty() {
+			t.Errorf("Status() after a conflict = %+v, want the rebase aborted", status)
+		}
+	})
+
+	t.Run("no upstream", func(t *testing.T) {
+		runGit(t, tmpDir, "checkout", "--quiet", "-b", "untracked")
+		if _, err := repo.Pull(false); !errors.Is(err, ErrNoUpstream) {
+			t.Errorf("Pull() error = %v, want ErrNoUpstream", err)
+		}
+	})
+}
+
+func TestFastForward(t *testing.T) {
+	repo, tmpDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+	other := setupRemote(t, tmpDir)
+
+	push := func(name string) {
+		t.Helper()
+		commitFile(t, other, name, name)
+		runGit(t, other, "push", "--quiet", "origin", "master")
+		runGit(t, tmpDir, "fetch", "--quiet", "origin")
+	}
+	head := func(ref string) string {
+		t.Helper()
+		out, err := repo.output("rev-parse", ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	t.Run("branch that isn't checked out", func(t *testing.T) {
+		runGit(t, tmpDir, "checkout", "--quiet", "-b", "feature")
+		push("one.txt")
+
+		result, err := repo.FastForward("master")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Commits != 1 || head("master") != head("origin/master") {
+			t.Errorf("FastForward() = %+v, want master moved to origin/master", result)
+		}
+		if repo.CurrentBranch() != "feature" {
+			t.Error("Expected feature to stay checked out")
+		}
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		result, err := repo.FastForward("master")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.From != result.To {
+			t.Errorf("FastForward() = %+v, want nothing to do", result)
+		}
+	})
+
+	t.Run("checked out and clean", func(t *testing.T) {
+		runGit(t, tmpDir, "checkout", "--quiet", "master")
+		push("two.txt")
+
+		if _, err := repo.FastForward("master"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "two.txt")); err != nil {
+			t.Error("Expected the working tree to follow the fast-forward")
+		}
+	})
+
+	t.Run("checked out with local changes", func(t *testing.T) {
+		push("three.txt")
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.txt"), []byte("local edit"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		defer runGit(t, tmpDir, "checkout", "--", "test.txt")
+
+		if _, err := repo.FastForward("master"); !errors.Is(err, ErrDirty) {
+			t.Errorf("FastForward() error = %v, want ErrDirty", err)
+		}
+	})
+
+	t.Run("diverged", func(t *testing.T) {
+		commitFile(t, tmpDir, "local.txt", "local")
+		runGit(t, tmpDir, "checkout", "--quiet", "feature")
+
+		if _, err := repo.FastForward("master"); !errors.Is(err, ErrDiverged) {
+			t.Errorf("FastForward() error = %v, want ErrDiverged", err)
+		}
+	})
+
+	t.Run("no upstream", func(t *testing.T) {
+		if _, err := repo.FastForward("feature"); !errors.Is(err, ErrNoUpstream) {
+			t.Errorf("FastForward() error = %v, want ErrNoUpstream", err)
+		}
+	})
+}
@@ -1,9 +1,11 @@
 package repo
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/kernelle-soft/gimme/internal/log"
 )
 
@@ -14,7 +16,13 @@ type Repo struct {
 	Name       string
 	Identifier string // Normalized remote URL (e.g. "github.com/user/repo") or path fallback
 	Pinned     bool
-	PinIndex   int // -1 if not pinned, otherwise the index in the pins list (lower = higher priority)
+	PinIndex   int   // -1 if not pinned, otherwise the index in the pins list (lower = higher priority)
+	Parent     *Repo // set for linked worktrees: the repository they belong to
+}
+
+// IsWorktree reports whether the repo is a linked worktree of another repo.
+func (r *Repo) IsWorktree() bool {
+	return r.Parent != nil
 }
 
 // NewRepo creates a new Repo with the Identifier automatically populated.
@@ -44,9 +52,14 @@ func NewPinnedRepo(gitRepo *git.Repository, path, name string, pinIndex int) Rep
 }
 
 // CurrentBranch returns the name of the current branch, or a short commit hash
-// if HEAD is detached.
+// if HEAD is detached. Before the first commit it's the branch HEAD points to.
 func (r *Repo) CurrentBranch() string {
 	head, err := r.Repository.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		if symbolic, err := r.Repository.Reference(plumbing.HEAD, false); err == nil {
+			return symbolic.Target().Short()
+		}
+	}
 	if err != nil {
 		log.Error("Error getting current branch", "path", r.Path, "err", err)
 		return ""
@@ -134,7 +147,7 @@ func NormalizeRemoteURL(url string) string {
 // IdentifierFromPath returns a stable identifier for a repository given its path.
 // Opens the repo to check for an origin remote.
 func IdentifierFromPath(repoPath string) string {
-	gitRepo, err := git.PlainOpen(repoPath)
+	gitRepo, err := Open(repoPath)
 	if err != nil {
 		return repoPath
 	}
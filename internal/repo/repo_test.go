@@ -150,6 +150,46 @@ func TestNewRepo(t *testing.T) {
 	})
 }
 
+func TestCurrentBranch(t *testing.T) {
+	t.Run("before the first commit", func(t *testing.T) {
+		tmpDir, err := os.MkdirTemp("", "gimme-test-*")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		gitRepo, err := git.PlainInit(tmpDir, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		repo := NewRepo(gitRepo, tmpDir, "empty")
+		if branch := repo.CurrentBranch(); branch != "master" {
+			t.Errorf("CurrentBranch() = %q, want %q", branch, "master")
+		}
+	})
+
+	t.Run("detached", func(t *testing.T) {
+		repo, _, cleanup := setupTestRepo(t)
+		defer cleanup()
+
+		head, err := repo.Repository.Head()
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, err := repo.Repository.Worktree()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Checkout(&git.CheckoutOptions{Hash: head.Hash()}); err != nil {
+			t.Fatal(err)
+		}
+		if branch := repo.CurrentBranch(); len(branch) != 7 {
+			t.Errorf("CurrentBranch() = %q, want a short commit hash", branch)
+		}
+	})
+}
+
 func TestNewPinnedRepo(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "gimme-test-*")
 	if err != nil {
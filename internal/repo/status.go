@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Operations that can be left in progress in a working tree.
+const (
+	OperationRebase     = "rebase"
+	OperationMerge      = "merge"
+	OperationCherryPick = "cherry-pick"
+	OperationRevert     = "revert"
+	OperationBisect     = "bisect"
+)
+
+// Status is the state of a working tree, as reported by git status.
+type Status struct {
+	Branch     string // empty when detached
+	Head       string // commit checked out, empty before the first commit
+	Upstream   string // empty when the branch doesn't track one
+	Ahead      int    // commits on the branch that aren't upstream
+	Behind     int    // commits upstream that aren't on the branch
+	Staged     int
+	Unstaged   int
+	Untracked  int
+	Conflicted int
+	Stashes    int
+	Operation  string // rebase, merge, etc. left in progress; empty if none
+}
+
+// IsDirty reports whether the working tree has changes, staged or not, or
+// untracked files.
+func (s Status) IsDirty() bool {
+	return s.Staged+s.Unstaged+s.Untracked+s.Conflicted > 0
+}
+
+// Status reads the state of the repository's working tree.
+func (r *Repo) Status() (Status, error) {
+	output, err := r.output("status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := parseStatus(output)
+	if loc, err := Locate(r.Path); err == nil {
+		status.Stashes = countStashes(loc.CommonDir)
+		status.Operation = operationInProgress(loc.GitDir)
+	}
+	return status, nil
+}
+
+// parseStatus parses porcelain v2 output with branch headers:
+//
+//	# branch.oid <commit>          ("(initial)" before the first commit)
+//	# branch.head <branch>         ("(detached)" when detached)
+//	# branch.upstream <upstream>   (optional)
+//	# branch.ab +<ahead> -<behind> (optional)
+//	1 <XY> ...                     (changed entry)
+//	2 <XY> ...                     (renamed or copied entry)
+//	u <XY> ...                     (unmerged entry)
+//	? <path>                       (untracked file)
+//
+// X is the staged state and Y the unstaged one, "." when unchanged.
+func parseStatus(output string) Status {
+	var s Status
+	for _, line := range strings.Split(output, "\n") {
+		kind, rest, _ := strings.Cut(line, " ")
+		switch kind {
+		case "#":
+			key, value, _ := strings.Cut(rest, " ")
+			switch key {
+			case "branch.oid":
+				if value != "(initial)" {
+					s.Head = value
+				}
+			case "branch.head":
+				if value != "(detached)" {
+					s.Branch = value
+				}
+			case "branch.upstream":
+				s.Upstream = value
+			case "branch.ab":
+				ahead, behind, _ := strings.Cut(value, " ")
+				s.Ahead, _ = strconv.Atoi(strings.TrimPrefix(ahead, "+"))
+				s.Behind, _ = strconv.Atoi(strings.TrimPrefix(behind, "-"))
+			}
+		case "1", "2":
+			if len(rest) < 2 {
+				continue
+			}
+			if rest[0] != '.' {
+				s.Staged++
+			}
+			if rest[1] != '.' {
+				s.Unstaged++
+			}
+		case "u":
+			s.Conflicted++
+		case "?":
+			s.Untracked++
+		}
+	}
+	return s
+}
+
+// countStashes counts the entries in the stash, which all of a repository's
+// worktrees share.
+func countStashes(commonDir string) int {
+	data, err := os.ReadFile(filepath.Join(commonDir, "logs", "refs", "stash"))
+	if err != nil {
+		return 0
+	}
+	return bytes.Count(data, []byte("\n"))
+}
+
+// operationInProgress returns the operation git left unfinished in a working
+// tree, judging by the state files in its git directory.
+func operationInProgress(gitDir string) string {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(gitDir, name))
+		return err == nil
+	}
+
+	switch {
+	case exists("rebase-merge"), exists("rebase-apply"):
+		return OperationRebase
+	case exists("MERGE_HEAD"):
+		return OperationMerge
+	case exists("CHERRY_PICK_HEAD"):
+		return OperationCherryPick
+	case exists("REVERT_HEAD"):
+		return OperationRevert
+	case exists("BISECT_LOG"):
+		return OperationBisect
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// runGit runs a git command in dir, failing the test with git's output if it
+// doesn't succeed.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, output)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	output := `# branch.oid 1234567890abcdef1234567890abcdef12345678
+# branch.head feature
+# branch.upstream origin/feature
+# branch.ab +2 -3
+1 M. N... 100644 100644 100644 abc abc staged.go
+1 .M N... 100644 100644 100644 abc abc unstaged.go
+1 MM N... 100644 100644 100644 abc abc both.go
+2 R. N... 100644 100644 100644 abc abc R100 new.go	old.go
+u UU N... 100644 100644 100644 100644 abc abc abc conflict.go
+? untracked.go
+? also-untracked.go
+`
+
+	expected := Status{
+		Branch:     "feature",
+		Head:       "1234567890abcdef1234567890abcdef12345678",
+		Upstream:   "origin/feature",
+		Ahead:      2,
+		Behind:     3,
+		Staged:     3,
+		Unstaged:   2,
+		Untracked:  2,
+		Conflicted: 1,
+	}
+	if got := parseStatus(output); got != expected {
+		t.Errorf("parseStatus() = %+v, want %+v", got, expected)
+	}
+
+	t.Run("detached before the first commit", func(t *testing.T) {
+		got := parseStatus("# branch.oid (initial)\n# branch.head (detached)\n")
+		if got != (Status{}) || got.IsDirty() {
+			t.Errorf("parseStatus() = %+v, want a clean, empty status", got)
+		}
+	})
+}
+
+func TestStatus(t *testing.T) {
+	repo, tmpDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	status, err := repo.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Branch != "master" || status.IsDirty() || status.Upstream != "" {
+		t.Errorf("Status() = %+v, want clean master without upstream", status)
+	}
+
+	t.Run("ahead and behind", func(t *testing.T) {
+		runGit(t, tmpDir, "branch", "base")
+		runGit(t, tmpDir, "commit", "--allow-empty", "-m", "ahead")
+		runGit(t, tmpDir, "branch", "--set-upstream-to", "base")
+
+		status, err := repo.Status()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Upstream != "base" || status.Ahead != 1 || status.Behind != 0 {
+			t.Errorf("Status() = %+v, want 1 ahead of base", status)
+		}
+	})
+
+	t.Run("changes and stashes", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.txt"), []byte("stashed"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, tmpDir, "stash")
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.txt"), []byte("changed"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, "new.txt"), []byte("new"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		status, err := repo.Status()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Unstaged != 1 || status.Untracked != 1 || status.Stashes != 1 || !status.IsDirty() {
+			t.Errorf("Status() = %+v, want 1 unstaged, 1 untracked and 1 stash", status)
+		}
+		runGit(t, tmpDir, "checkout", "--", "test.txt")
+		os.Remove(filepath.Join(tmpDir, "new.txt"))
+	})
+
+	t.Run("merge in progress", func(t *testing.T) {
+		runGit(t, tmpDir, "checkout", "-b", "other")
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.txt"), []byte("other"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, tmpDir, "commit", "-am", "other")
+		runGit(t, tmpDir, "checkout", "master")
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.txt"), []byte("master"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, tmpDir, "commit", "-am", "master")
+
+		cmd := exec.Command("git", "merge", "other")
+		cmd.Dir = tmpDir
+		if err := cmd.Run(); err == nil {
+			t.Fatal("Expected the merge to conflict")
+		}
+
+		status, err := repo.Status()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Operation != OperationMerge || status.Conflicted != 1 {
+			t.Errorf("Status() = %+v, want a conflicted merge in progress", status)
+		}
+	})
+}
@@ -0,0 +1,190 @@
+package repo
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kernelle-soft/gimme/internal/path"
+)
+
+// Worktree is a working tree attached to a repository, as reported by
+// git worktree list.
+type Worktree struct {
+	Path     string
+	Head     string // commit checked out
+	Branch   string // empty when detached
+	Main     bool   // the repository's main working tree
+	Bare     bool
+	Detached bool
+	Locked   bool
+	Prunable bool // the worktree's directory is gone
+}
+
+// IsDirty reports whether the worktree has uncommitted changes or untracked
+// files.
+func (w Worktree) IsDirty() bool {
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = w.Path
+
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return len(strings.TrimSpace(string(output))) > 0
+}
+
+// Worktrees returns every worktree of the repository, main working tree first.
+func (r *Repo) Worktrees() ([]Worktree, error) {
+	cmd := exec.Command("git", "worktree", "list", "--porcelain")
+	cmd.Dir = r.Path
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseWorktrees(string(output)), nil
+}
+
+// parseWorktrees parses porcelain output, where each worktree is a block of
+// lines separated by a blank line:
+//
+//	worktree /path/to/worktree
+//	HEAD <commit>
+//	branch refs/heads/<branch>   (or "detached")
+//	locked [reason]              (optional)
+//	prunable [reason]            (optional)
+func parseWorktrees(output string) []Worktree {
+	worktrees := []Worktree{}
+	var current *Worktree
+
+	for _, line := range strings.Split(output, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "worktree":
+			worktrees = append(worktrees, Worktree{Path: value, Main: len(worktrees) == 0})
+			current = &worktrees[len(worktrees)-1]
+		case "HEAD":
+			if current != nil {
+				current.Head = value
+			}
+		case "branch":
+			if current != nil {
+				current.Branch = strings.TrimPrefix(value, "refs/heads/")
+			}
+		case "detached":
+			if current != nil {
+				current.Detached = true
+			}
+		case "bare":
+			if current != nil {
+				current.Bare = true
+			}
+		case "locked":
+			if current != nil {
+				current.Locked = true
+			}
+		case "prunable":
+			if current != nil {
+				current.Prunable = true
+			}
+		}
+	}
+
+	return worktrees
+}
+
+// WorktreeForBranch returns the worktree that has branch checked out.
+func (r *Repo) WorktreeForBranch(branch string) (Worktree, bool) {
+	worktrees, err := r.Worktrees()
+	if err != nil {
+		return Worktree{}, false
+	}
+	for _, wt := range worktrees {
+		if wt.Branch == branch {
+			return wt, true
+		}
+	}
+	return Worktree{}, false
+}
+
+// WorktreePath returns where a new worktree for branch should go according to
+// layout. Layouts may use {repo} and {branch}; relative layouts are resolved
+// against the repository's parent directory. Slashes in branch names are
+// replaced so every worktree sits at the same depth.
+func (r *Repo) WorktreePath(branch, layout string) (string, error) {
+	replacer := strings.NewReplacer(
+		"{repo}", r.Name,
+		"{branch}", strings.ReplaceAll(branch, "/", "-"),
+	)
+	expanded, err := path.Normalize(replacer.Replace(layout))
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(expanded) {
+		expanded = filepath.Join(filepath.Dir(r.Path), expanded)
+	}
+	return expanded, nil
+}
+
+// AddWorktree creates a worktree at path with branch checked out. When create
+// is set, a new branch is created from base (or HEAD if base is empty).
+func (r *Repo) AddWorktree(path, branch string, create bool, base string) error {
+	args := []string{"worktree", "add"}
+	if create {
+		args = append(args, "-b", branch, path)
+		if base != "" {
+			args = append(args, base)
+		}
+	} else {
+		args = append(args, path, branch)
+	}
+	return r.git(args...)
+}
+
+// RemoveWorktree removes the worktree at path. Without force, git refuses to
+// remove worktrees with local changes.
+func (r *Repo) RemoveWorktree(path string, force bool) error {
+	args := []string{"worktree", "remove"}
+	if force {
+		args = append(args, "--force")
+	}
+	return r.git(append(args, path)...)
+}
+
+// PruneWorktrees removes administrative data for worktrees whose directories
+// no longer exist. Returns the names of the pruned worktrees.
+func (r *Repo) PruneWorktrees() ([]string, error) {
+	cmd := exec.Command("git", "worktree", "prune", "--verbose")
+	cmd.Dir = r.Path
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, commandError(err, output)
+	}
+
+	pruned := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			pruned = append(pruned, line)
+		}
+	}
+	return pruned, nil
+}
+
+// BranchExists reports whether a local branch exists.
+func (r *Repo) BranchExists(branch string) bool {
+	return r.refExists("refs/heads/" + branch)
+}
+
+// RemoteBranchExists reports whether origin has a branch of that name.
+func (r *Repo) RemoteBranchExists(branch string) bool {
+	return r.refExists("refs/remotes/origin/" + branch)
+}
+
+func (r *Repo) refExists(ref string) bool {
+	cmd := exec.Command("git", "show-ref", "--verify", "--quiet", ref)
+	cmd.Dir = r.Path
+	return cmd.Run() == nil
+}
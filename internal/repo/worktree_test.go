@@ -0,0 +1,135 @@
+package repo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseWorktrees(t *testing.T) {
+	output := `worktree /src/gimme
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /src/gimme.worktrees/feature-x
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/feature/x
+locked
+
+worktree /src/gimme.worktrees/review
+HEAD 3333333333333333333333333333333333333333
+detached
+prunable gitdir file points to non-existent location
+
+`
+
+	expected := []Worktree{
+		{Path: "/src/gimme", Head: "1111111111111111111111111111111111111111", Branch: "main", Main: true},
+		{Path: "/src/gimme.worktrees/feature-x", Head: "2222222222222222222222222222222222222222", Branch: "feature/x", Locked: true},
+		{Path: "/src/gimme.worktrees/review", Head: "3333333333333333333333333333333333333333", Detached: true, Prunable: true},
+	}
+
+	worktrees := parseWorktrees(output)
+	if !reflect.DeepEqual(worktrees, expected) {
+		t.Errorf("parseWorktrees() = %+v, want %+v", worktrees, expected)
+	}
+}
+
+func TestWorktreePath(t *testing.T) {
+	r := &Repo{Name: "gimme", Path: "/src/gimme"}
+
+	tests := []struct {
+		name     string
+		branch   string
+		layout   string
+		expected string
+	}{
+		{
+			name:     "default layout",
+			branch:   "feature-x",
+			layout:   "{repo}.worktrees/{branch}",
+			expected: "/src/gimme.worktrees/feature-x",
+		},
+		{
+			name:     "slashes in branch are flattened",
+			branch:   "feature/x",
+			layout:   "{repo}.worktrees/{branch}",
+			expected: "/src/gimme.worktrees/feature-x",
+		},
+		{
+			name:     "absolute layout",
+			branch:   "fix",
+			layout:   "/worktrees/{repo}/{branch}",
+			expected: "/worktrees/gimme/fix",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.WorktreePath(tt.branch, tt.layout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.expected {
+				t.Errorf("WorktreePath(%q, %q) = %q, want %q", tt.branch, tt.layout, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAddAndRemoveWorktree(t *testing.T) {
+	repo, tmpDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	wtDir, err := os.MkdirTemp("", "gimme-worktree-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(wtDir)
+	wtPath := filepath.Join(wtDir, "feature")
+
+	if repo.BranchExists("feature") {
+		t.Fatal("Expected branch feature not to exist yet")
+	}
+	if err := repo.AddWorktree(wtPath, "feature", true, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !repo.BranchExists("feature") {
+		t.Error("Expected AddWorktree to create branch feature")
+	}
+
+	wt, ok := repo.WorktreeForBranch("feature")
+	if !ok {
+		t.Fatal("Expected a worktree for branch feature")
+	}
+	resolved, _ := filepath.EvalSymlinks(wtPath)
+	if wt.Path != resolved && wt.Path != wtPath {
+		t.Errorf("Worktree path = %q, want %q", wt.Path, wtPath)
+	}
+	if wt.Main {
+		t.Error("Expected linked worktree not to be the main one")
+	}
+
+	worktrees, err := repo.Worktrees()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(worktrees) != 2 || !worktrees[0].Main {
+		t.Errorf("Expected main worktree plus one linked worktree, got %+v", worktrees)
+	}
+
+	if err := repo.AddWorktree(filepath.Join(wtDir, "again"), "feature", false, ""); err == nil {
+		t.Error("Expected checking out the same branch twice to fail")
+	}
+
+	if err := repo.RemoveWorktree(wtPath, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := repo.WorktreeForBranch("feature"); ok {
+		t.Error("Expected worktree to be gone after RemoveWorktree")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".git", "worktrees", "feature")); !os.IsNotExist(err) {
+		t.Error("Expected git's worktree metadata to be removed")
+	}
+}
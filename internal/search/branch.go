@@ -1 +1,78 @@
-package search
\ No newline at end of file
+package search
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/repo"
+)
+
+// What to do when no worktree has the requested branch checked out.
+const (
+	MissingBranchNone     = "none"     // report it and stay put
+	MissingBranchWorktree = "worktree" // create a worktree for it
+	MissingBranchCheckout = "checkout" // check it out in the main tree if it's clean
+)
+
+var (
+	ErrNoSuchBranch        = errors.New("branch does not exist")
+	ErrBranchNotCheckedOut = errors.New("branch is not checked out in any worktree")
+	ErrMainTreeDirty       = errors.New("main working tree has local changes")
+)
+
+// BranchTarget is where a repo@branch jump lands.
+type BranchTarget struct {
+	Path       string
+	Created    bool // a worktree was created for the branch
+	CheckedOut bool // the branch was checked out in the main tree
+}
+
+// SplitBranch splits a repo@branch query at its first "@". The branch is empty
+// when the query has none.
+func SplitBranch(query string) (string, string) {
+	name, branch, _ := strings.Cut(query, "@")
+	return name, branch
+}
+
+// ResolveBranch finds the worktree of r that has branch checked out. When
+// there is none, onMissing decides whether to create a worktree, check the
+// branch out in the main tree, or fail. If r is itself a linked worktree, its
+// main repository is used.
+func ResolveBranch(r *repo.Repo, branch, onMissing string) (BranchTarget, error) {
+	if r.Parent != nil {
+		r = r.Parent
+	}
+
+	if wt, ok := r.WorktreeForBranch(branch); ok {
+		return BranchTarget{Path: wt.Path}, nil
+	}
+
+	if !r.BranchExists(branch) && !r.RemoteBranchExists(branch) {
+		return BranchTarget{}, ErrNoSuchBranch
+	}
+
+	switch onMissing {
+	case MissingBranchWorktree:
+		wtPath, err := r.WorktreePath(branch, config.GetWorktreeLayout())
+		if err != nil {
+			return BranchTarget{}, err
+		}
+		if err := r.AddWorktree(wtPath, branch, false, ""); err != nil {
+			return BranchTarget{}, err
+		}
+		return BranchTarget{Path: wtPath, Created: true}, nil
+
+	case MissingBranchCheckout:
+		if (repo.Worktree{Path: r.Path}).IsDirty() {
+			return BranchTarget{}, ErrMainTreeDirty
+		}
+		if err := r.Checkout(branch); err != nil {
+			return BranchTarget{}, err
+		}
+		return BranchTarget{Path: r.Path, CheckedOut: true}, nil
+
+	default:
+		return BranchTarget{}, ErrBranchNotCheckedOut
+	}
+}
@@ -0,0 +1,129 @@
+package search
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestSplitBranch(t *testing.T) {
+	tests := []struct {
+		query  string
+		name   string
+		branch string
+	}{
+		{query: "kernelle", name: "kernelle", branch: ""},
+		{query: "kernelle@feature-x", name: "kernelle", branch: "feature-x"},
+		{query: "kernelle@feature/x", name: "kernelle", branch: "feature/x"},
+		{query: "@feature-x", name: "", branch: "feature-x"},
+		{query: "kernelle@", name: "kernelle", branch: ""},
+		{query: "kernelle@fix@2", name: "kernelle", branch: "fix@2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			name, branch := SplitBranch(tt.query)
+			if name != tt.name || branch != tt.branch {
+				t.Errorf("SplitBranch(%q) = (%q, %q), want (%q, %q)", tt.query, name, branch, tt.name, tt.branch)
+			}
+		})
+	}
+}
+
+func TestResolveBranch(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-branch-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+
+	viper.Set("worktrees.layout", filepath.Join(tmpDir, "worktrees", "{repo}-{branch}"))
+	defer viper.Reset()
+
+	mainPath := filepath.Join(tmpDir, "api")
+	initRepo(t, mainPath)
+	runGit(t, mainPath, "commit", "--allow-empty", "-m", "initial")
+	runGit(t, mainPath, "branch", "existing")
+	runGit(t, mainPath, "branch", "other")
+	runGit(t, mainPath, "worktree", "add", filepath.Join(tmpDir, "checked-out"), "-b", "checked-out")
+
+	r := FindRepoForPath(mainPath)
+	if r == nil {
+		t.Fatal("Expected to find the test repository")
+	}
+
+	t.Run("branch in a worktree", func(t *testing.T) {
+		target, err := ResolveBranch(r, "checked-out", MissingBranchNone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if target.Path != filepath.Join(tmpDir, "checked-out") || target.Created {
+			t.Errorf("ResolveBranch() = %+v, want the existing worktree", target)
+		}
+	})
+
+	t.Run("branch in the main tree", func(t *testing.T) {
+		target, err := ResolveBranch(r, r.CurrentBranch(), MissingBranchNone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if target.Path != mainPath {
+			t.Errorf("ResolveBranch() = %+v, want the main tree", target)
+		}
+	})
+
+	t.Run("unknown branch", func(t *testing.T) {
+		if _, err := ResolveBranch(r, "nope", MissingBranchWorktree); !errors.Is(err, ErrNoSuchBranch) {
+			t.Errorf("ResolveBranch() error = %v, want ErrNoSuchBranch", err)
+		}
+	})
+
+	t.Run("not checked out", func(t *testing.T) {
+		if _, err := ResolveBranch(r, "existing", MissingBranchNone); !errors.Is(err, ErrBranchNotCheckedOut) {
+			t.Errorf("ResolveBranch() error = %v, want ErrBranchNotCheckedOut", err)
+		}
+	})
+
+	t.Run("creates a worktree", func(t *testing.T) {
+		target, err := ResolveBranch(r, "existing", MissingBranchWorktree)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := filepath.Join(tmpDir, "worktrees", "api-existing")
+		if target.Path != expected || !target.Created {
+			t.Errorf("ResolveBranch() = %+v, want a new worktree at %s", target, expected)
+		}
+		if _, ok := r.WorktreeForBranch("existing"); !ok {
+			t.Error("Expected a worktree for branch existing")
+		}
+	})
+
+	t.Run("refuses to check out over local changes", func(t *testing.T) {
+		dirty := filepath.Join(mainPath, "dirty.txt")
+		if err := os.WriteFile(dirty, []byte("wip"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(dirty)
+
+		if _, err := ResolveBranch(r, "other", MissingBranchCheckout); !errors.Is(err, ErrMainTreeDirty) {
+			t.Errorf("ResolveBranch() error = %v, want ErrMainTreeDirty", err)
+		}
+	})
+
+	t.Run("checks out in a clean main tree", func(t *testing.T) {
+		target, err := ResolveBranch(r, "other", MissingBranchCheckout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if target.Path != mainPath || !target.CheckedOut {
+			t.Errorf("ResolveBranch() = %+v, want the main tree", target)
+		}
+		if branch := r.CurrentBranch(); branch != "other" {
+			t.Errorf("CurrentBranch() = %q, want other", branch)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package search
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/index"
+	"github.com/kernelle-soft/gimme/internal/repo"
+)
+
+// Candidates returns every repository in the search folders, sorted by name,
+// without opening any of them. It relies on the index, only re-reading
+// directories that changed and never falling back to a live scan, so it's
+// fast enough for shell completion.
+func Candidates() []index.Entry {
+	ctx, cancel := searchContext()
+	defer cancel()
+
+	idx := loadIndex()
+	entries := discover(ctx, config.GetSearchFolders(), idx, false)
+	saveIndex(idx)
+
+	slices.SortFunc(entries, func(a, b index.Entry) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	return entries
+}
+
+// BestCandidate returns the candidate a jump to query would take: the best
+// match, ranked with the same pin and frecency boosts, without opening any
+// repository.
+func BestCandidate(query string) (index.Entry, bool) {
+	matched := filterEntries(Candidates(), query)
+	if len(matched) == 0 {
+		return index.Entry{}, false
+	}
+
+	pins := config.GetPinnedRepos()
+	byPath := make(map[string]index.Entry, len(matched))
+	repos := make([]repo.Repo, len(matched))
+	for i, e := range matched {
+		byPath[e.Path] = e
+		repos[i] = repo.Repo{Path: e.Path, Name: e.Name, Identifier: e.Identifier, PinIndex: slices.Index(pins, e.Path)}
+		repos[i].Pinned = repos[i].PinIndex >= 0
+	}
+	Rank(repos, query, loadUsage())
+	return byPath[repos[0].Path], true
+}
@@ -0,0 +1,68 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestCandidates(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-complete-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(tmpDir, "cache"))
+
+	root := filepath.Join(tmpDir, "src")
+	initRepo(t, filepath.Join(root, "zeta"))
+	initRepo(t, filepath.Join(root, "org", "alpha"))
+
+	viper.Set("search-folders", []string{root})
+	defer viper.Reset()
+
+	names := func() []string {
+		var result []string
+		for _, e := range Candidates() {
+			result = append(result, e.Name)
+		}
+		return result
+	}
+
+	if found := names(); !slices.Equal(found, []string{"alpha", "zeta"}) {
+		t.Fatalf("Candidates() = %v, want [alpha zeta]", found)
+	}
+
+	t.Run("index is kept up to date", func(t *testing.T) {
+		initRepo(t, filepath.Join(root, "org", "beta"))
+		if found := names(); !slices.Equal(found, []string{"alpha", "beta", "zeta"}) {
+			t.Errorf("Candidates() = %v, want [alpha beta zeta]", found)
+		}
+	})
+
+	t.Run("best candidate", func(t *testing.T) {
+		if e, ok := BestCandidate("alp"); !ok || e.Name != "alpha" {
+			t.Errorf("BestCandidate(\"alp\") = %v, %v, want alpha", e, ok)
+		}
+		if _, ok := BestCandidate("nothing"); ok {
+			t.Error("BestCandidate(\"nothing\") found a match, want none")
+		}
+	})
+
+	t.Run("best candidate is ranked like a jump", func(t *testing.T) {
+		t.Setenv("XDG_STATE_HOME", filepath.Join(tmpDir, "state"))
+		initRepo(t, filepath.Join(root, "org", "app"))
+		initRepo(t, filepath.Join(root, "org", "apple"))
+
+		if e, _ := BestCandidate("ap"); e.Name != "app" {
+			t.Fatalf("BestCandidate(\"ap\") = %v, want app before any pins", e)
+		}
+		viper.Set("pins.repositories", []string{filepath.Join(root, "org", "apple")})
+		if e, _ := BestCandidate("ap"); e.Name != "apple" {
+			t.Errorf("BestCandidate(\"ap\") = %v, want the pinned apple", e)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package search
+
+import (
+	"context"
+
+	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/index"
+	"github.com/kernelle-soft/gimme/internal/log"
+)
+
+// sharedIndex is loaded once per process and shared by every search.
+var sharedIndex *index.Index
+
+// loadIndex returns the repository index, or nil if it can't be read. Searches
+// still work without it, they just scan every directory.
+func loadIndex() *index.Index {
+	if sharedIndex != nil {
+		return sharedIndex
+	}
+
+	idx, err := index.Load()
+	if err != nil {
+		log.Debug("Could not load repository index: {}", err)
+		return nil
+	}
+	sharedIndex = idx
+	return sharedIndex
+}
+
+// saveIndex persists any changes discovery made to the index.
+func saveIndex(idx *index.Index) {
+	if idx == nil {
+		return
+	}
+	if err := idx.Save(); err != nil {
+		log.Debug("Could not save repository index: {}", err)
+	}
+}
+
+// RebuildIndex discards the repository index and rescans every search folder.
+// Returns the number of repositories found.
+func RebuildIndex() (int, error) {
+	idx, err := index.Load()
+	if err != nil {
+		return 0, err
+	}
+	sharedIndex = idx
+
+	idx.Reset()
+	found := discover(context.Background(), config.GetSearchFolders(), idx, true)
+	return len(found), idx.Save()
+}
+
+// ClearIndex deletes the repository index. The next search rebuilds it.
+func ClearIndex() error {
+	idx, err := index.Load()
+	if err != nil {
+		return err
+	}
+	sharedIndex = nil
+	return idx.Remove()
+}
+
+// IndexPath returns where the repository index is stored.
+func IndexPath() (string, error) {
+	idx, err := index.Load()
+	if err != nil {
+		return "", err
+	}
+	return idx.Path(), nil
+}
@@ -0,0 +1,101 @@
+package search
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/kernelle-soft/gimme/internal/directive"
+	"github.com/kernelle-soft/gimme/internal/frecency"
+	"github.com/kernelle-soft/gimme/internal/history"
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/repo"
+)
+
+// Land moves the shell to target and records the jump: in the frecency
+// history, credited to root since that's what searches rank, and in the
+// directory history used by 'gimme back'. Failing to record never fails the
+// jump itself.
+func Land(root, target string) {
+	directive.Emit(Landing(target)...)
+	if err := frecency.Record(root); err != nil {
+		log.Warning("Could not record jump history: {}", err)
+	}
+	if err := history.Record(target); err != nil {
+		log.Warning("Could not record directory history: {}", err)
+	}
+}
+
+// Landing returns the directives for jumping to target. Inside a repository
+// they also set GIMME_REPO to its identifier, GIMME_BRANCH to its current
+// branch and the variables in its env, and run its on-enter command (see
+// SettingsFor); elsewhere they clear them all so they never describe a
+// repository the shell has left.
+func Landing(target string) []directive.Directive {
+	directives := []directive.Directive{directive.Cd(target)}
+
+	r := FindRepoForPath(target)
+	if r == nil {
+		directives = append(directives, directive.Unsetenv(directive.RepoEnv), directive.Unsetenv(directive.BranchEnv))
+		return append(directives, envDirectives(nil)...)
+	}
+
+	directives = append(directives, directive.Setenv(directive.RepoEnv, r.Identifier))
+	if branch := r.CurrentBranch(); branch != "" {
+		directives = append(directives, directive.Setenv(directive.BranchEnv, branch))
+	} else {
+		directives = append(directives, directive.Unsetenv(directive.BranchEnv))
+	}
+
+	root := r.Path
+	if loc, err := repo.Locate(target); err == nil {
+		root = loc.WorktreeRoot
+	}
+	settings := SettingsFor(r.Identifier, root)
+	if settings.Untrusted {
+		WarnUntrusted(root)
+	}
+	directives = append(directives, envDirectives(settings.Env)...)
+
+	if settings.OnEnter != "" {
+		directives = append(directives, directive.Run(settings.OnEnter))
+	}
+	return directives
+}
+
+// WarnUntrusted tells the user why the on-enter command and env of the
+// .gimme.yaml in root were skipped.
+func WarnUntrusted(root string) {
+	log.Warning("Skipping on-enter and env in \"{}\" until you trust it with 'gimme trust'.", filepath.Join(root, repo.LocalConfigFile))
+}
+
+// envDirectives sets the variables in env and unsets the ones an earlier jump
+// set that env doesn't have.
+func envDirectives(env map[string]string) []directive.Directive {
+	directives := []directive.Directive{}
+	names := []string{}
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		if !directive.IsValidName(name) {
+			log.Warning("Ignoring invalid environment variable name \"{}\" in {}.", name, repo.LocalConfigFile)
+			continue
+		}
+		directives = append(directives, directive.Setenv(name, env[name]))
+		names = append(names, name)
+	}
+
+	previous := os.Getenv(directive.EnvNamesEnv)
+	for _, name := range strings.Split(previous, ",") {
+		if directive.IsValidName(name) && !slices.Contains(names, name) {
+			directives = append(directives, directive.Unsetenv(name))
+		}
+	}
+
+	if len(names) > 0 {
+		directives = append(directives, directive.Setenv(directive.EnvNamesEnv, strings.Join(names, ",")))
+	} else if previous != "" {
+		directives = append(directives, directive.Unsetenv(directive.EnvNamesEnv))
+	}
+	return directives
+}
@@ -0,0 +1,296 @@
+package search
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kernelle-soft/gimme/internal/frecency"
+	"github.com/kernelle-soft/gimme/internal/repo"
+)
+
+// Match tiers. A candidate's score falls within the band of the best tier it
+// satisfies, minus a small penalty so tighter matches win within a tier.
+const (
+	scoreExact     = 1000
+	scorePrefix    = 800
+	scoreBoundary  = 600
+	scoreSubstring = 400
+	scoreFuzzy     = 200
+
+	// maxPenalty keeps penalties inside a tier's band so tiers never overlap.
+	maxPenalty = 150
+
+	// pinBoost is added to a pinned repo's score to lift it above peers in
+	// the same tier. Boosted scores are capped below the tier above (see
+	// tierCeiling), so a pin never beats a better match.
+	pinBoost = 150
+
+	// frecencyWeight scales the log of a repo's frecency score into a boost.
+	frecencyWeight = 30
+
+	// maxBoost caps the combined pin and frecency boost, so however often a
+	// repo is used, a closer match in the same tier can still beat it. Boosts
+	// never lift a match into the tier above either way (see tierCeiling).
+	maxBoost = 190
+
+	// trailingPenalty is taken off a segmented match for each candidate segment
+	// after the one the query's last segment matched, so "acme/api" prefers
+	// acme/api over acme/api/docs.
+	trailingPenalty = 40
+)
+
+// Score rates how well query matches candidate. It returns 0 when the query
+// doesn't match at all, and higher values for better matches:
+//
+//	exact > prefix > word-boundary > substring > subsequence
+//
+// A word-boundary match is the query starting a word of the candidate, or the
+// query's letters each starting one, in order: "fb" matches fooBar.
+//
+// Matching is smart-case: case-insensitive unless the query has an uppercase
+// letter. Word boundaries are always found in the candidate as written, so
+// camelCase humps count either way.
+func Score(query, candidate string) int {
+	if query == "" {
+		return 1
+	}
+
+	original := candidate
+	if !hasUpper(query) {
+		candidate = strings.ToLower(candidate)
+		if len(candidate) != len(original) {
+			// Lowercasing changed the length, so positions no longer line up
+			original = candidate
+		}
+	}
+
+	extra := len(candidate) - len(query)
+
+	if candidate == query {
+		return scoreExact
+	}
+
+	if strings.HasPrefix(candidate, query) {
+		return scorePrefix - penalty(extra)
+	}
+
+	first := strings.Index(candidate, query)
+	for i := first; i >= 0; {
+		if isBoundary(original, i) {
+			return scoreBoundary - penalty(extra)
+		}
+		next := strings.Index(candidate[i+1:], query)
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+
+	if len(query) > 1 && isSubsequence(query, initials(original, candidate)) {
+		return scoreBoundary - penalty(extra)
+	}
+
+	if first >= 0 {
+		return scoreSubstring - penalty(first+extra)
+	}
+
+	gaps, ok := subsequenceGaps(query, candidate)
+	if !ok {
+		return 0
+	}
+	return scoreFuzzy - penalty(gaps+extra)
+}
+
+// MatchRepo rates how well query matches a repository. Queries without a "/"
+// are matched against the repository's name. Queries with one are matched
+// segment by segment against its identifier (e.g. github.com/acme/api) and its
+// path, whichever scores higher, so "acme/api" or "gitlab.com/acme" can tell
+// apart repositories with the same name.
+func MatchRepo(query, name, identifier, path string) int {
+	if !strings.Contains(query, "/") {
+		return Score(query, name)
+	}
+
+	segments := splitSegments(query)
+	if len(segments) == 0 {
+		return Score("", name)
+	}
+	return max(
+		scoreSegments(segments, splitSegments(identifier)),
+		scoreSegments(segments, splitSegments(path)),
+	)
+}
+
+// IsUniqueExact reports whether exactly one of repos matches query exactly, so
+// there's nothing to choose between.
+func IsUniqueExact(query string, repos []repo.Repo) bool {
+	exact := 0
+	for _, r := range repos {
+		if MatchRepo(query, r.Name, r.Identifier, r.Path) == scoreExact {
+			exact++
+		}
+	}
+	return exact == 1
+}
+
+// scoreSegments matches query segments against candidate segments in order.
+// Every segment but the last must match a candidate segment as a substring or
+// better; the last may match like any single query. The score is the last
+// segment's, less trailingPenalty for each candidate segment after its match,
+// but never below the floor of its tier.
+func scoreSegments(query, candidate []string) int {
+	next := 0
+	for _, segment := range query[:len(query)-1] {
+		found := false
+		for ; next < len(candidate); next++ {
+			if Score(segment, candidate[next]) > scoreFuzzy {
+				found = true
+				next++
+				break
+			}
+		}
+		if !found {
+			return 0
+		}
+	}
+
+	last := query[len(query)-1]
+	best, bestAt := 0, 0
+	for i := next; i < len(candidate); i++ {
+		// On a tie, prefer the later segment; it leaves fewer trailing ones.
+		if score := Score(last, candidate[i]); score > best || (score == best && score > 0) {
+			best, bestAt = score, i
+		}
+	}
+	if best == 0 {
+		return 0
+	}
+
+	trailing := len(candidate) - 1 - bestAt
+	return max(best-trailing*trailingPenalty, tierFloor(best))
+}
+
+// tierFloor returns the lowest score in the tier that score falls in.
+func tierFloor(score int) int {
+	for _, tier := range []int{scoreFuzzy, scoreSubstring, scoreBoundary, scorePrefix} {
+		if score <= tier {
+			return tier - maxPenalty
+		}
+	}
+	return scoreExact - maxPenalty
+}
+
+// tierCeiling returns the highest score a boost may lift score to: one below
+// the floor of the tier above it. Exact matches have no ceiling.
+func tierCeiling(score int) int {
+	tiers := []int{scoreFuzzy, scoreSubstring, scoreBoundary, scorePrefix, scoreExact}
+	for i, tier := range tiers[:len(tiers)-1] {
+		if score <= tier {
+			return tiers[i+1] - maxPenalty - 1
+		}
+	}
+	return math.MaxInt
+}
+
+func splitSegments(s string) []string {
+	return slices.DeleteFunc(strings.Split(s, "/"), func(segment string) bool {
+		return segment == ""
+	})
+}
+
+// Rank sorts repos best match first. Pinned and frequently/recently used repos
+// get a boost, and ties fall back to pin order and then name. usage may be nil.
+func Rank(repos []repo.Repo, query string, usage *frecency.Store) {
+	now := time.Now()
+	scores := make(map[string]int, len(repos))
+	for _, r := range repos {
+		scores[r.Path] = rankScore(r, query, usage.Score(r.Path, now))
+	}
+
+	slices.SortStableFunc(repos, func(a, b repo.Repo) int {
+		if diff := scores[b.Path] - scores[a.Path]; diff != 0 {
+			return diff
+		}
+		return comparePins(a, b)
+	})
+}
+
+// rankScore is a repo's match score including its pin and frecency boosts.
+func rankScore(r repo.Repo, query string, frecent float64) int {
+	score := MatchRepo(query, r.Name, r.Identifier, r.Path)
+	if score == 0 {
+		return 0
+	}
+
+	boost := int(frecencyWeight * math.Log2(1+frecent))
+	if r.Pinned {
+		boost += pinBoost - min(r.PinIndex, maxPenalty)
+	}
+	return min(score+min(boost, maxBoost), tierCeiling(score))
+}
+
+// penalty clamps a tiebreaker so it stays within a tier's band.
+func penalty(n int) int {
+	return min(max(n, 0), maxPenalty)
+}
+
+// separators are the characters that separate words.
+const separators = "-_./ "
+
+// isBoundary reports whether position i in s starts a word: the start of the
+// string, the character after a separator, or a lower-to-upper camelCase hump.
+func isBoundary(s string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := rune(s[i-1])
+	if strings.ContainsRune(separators, prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(rune(s[i]))
+}
+
+// initials returns the characters of candidate that start a word, finding the
+// words in original, which is candidate before any case folding.
+func initials(original, candidate string) string {
+	var result strings.Builder
+	for i := range len(original) {
+		if isBoundary(original, i) && !strings.ContainsRune(separators, rune(original[i])) {
+			result.WriteByte(candidate[i])
+		}
+	}
+	return result.String()
+}
+
+// isSubsequence reports whether query's characters appear in s in order.
+func isSubsequence(query, s string) bool {
+	_, ok := subsequenceGaps(query, s)
+	return ok
+}
+
+// subsequenceGaps matches query as a subsequence of candidate and returns the
+// number of skipped characters between the first and last matched character.
+func subsequenceGaps(query, candidate string) (int, bool) {
+	qi := 0
+	start, gaps := -1, 0
+	for ci := 0; ci < len(candidate) && qi < len(query); ci++ {
+		if candidate[ci] == query[qi] {
+			if start < 0 {
+				start = ci
+			}
+			qi++
+			continue
+		}
+		if start >= 0 {
+			gaps++
+		}
+	}
+	return gaps, qi == len(query)
+}
+
+func hasUpper(s string) bool {
+	return strings.ContainsFunc(s, unicode.IsUpper)
+}
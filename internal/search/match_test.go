@@ -0,0 +1,244 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kernelle-soft/gimme/internal/frecency"
+	"github.com/kernelle-soft/gimme/internal/repo"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		candidate string
+		matches   bool
+	}{
+		{name: "exact", query: "api", candidate: "api", matches: true},
+		{name: "prefix", query: "kern", candidate: "kernelle", matches: true},
+		{name: "word boundary", query: "api", candidate: "legacy-api", matches: true},
+		{name: "camel case boundary", query: "Api", candidate: "legacyApi", matches: true},
+		{name: "initials", query: "fb", candidate: "fooBar", matches: true},
+		{name: "substring", query: "nel", candidate: "kernelle", matches: true},
+		{name: "subsequence", query: "knl", candidate: "kernelle", matches: true},
+		{name: "no match", query: "xyz", candidate: "kernelle", matches: false},
+		{name: "out of order", query: "lk", candidate: "kernelle", matches: false},
+		{name: "lowercase query ignores case", query: "gimme", candidate: "Gimme", matches: true},
+		{name: "uppercase query is case sensitive", query: "Gimme", candidate: "gimme", matches: false},
+		{name: "empty query matches everything", query: "", candidate: "anything", matches: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := Score(tt.query, tt.candidate)
+			if (score > 0) != tt.matches {
+				t.Errorf("Score(%q, %q) = %d, want match = %v", tt.query, tt.candidate, score, tt.matches)
+			}
+		})
+	}
+}
+
+func TestScoreBoundaries(t *testing.T) {
+	tests := []struct {
+		query     string
+		candidate string
+	}{
+		{query: "api", candidate: "legacyApi"},
+		{query: "Api", candidate: "legacyApi"},
+		{query: "fb", candidate: "fooBar"},
+		{query: "fb", candidate: "foo-bar"},
+		{query: "lga", candidate: "legacy-gateway-api"},
+		{query: "FB", candidate: "FooBar"},
+	}
+
+	for _, tt := range tests {
+		score := Score(tt.query, tt.candidate)
+		if score <= scoreBoundary-maxPenalty || score > scoreBoundary {
+			t.Errorf("Score(%q, %q) = %d, want a word-boundary match", tt.query, tt.candidate, score)
+		}
+	}
+
+	if score := Score("Fb", "fooBar"); score > scoreBoundary-maxPenalty {
+		t.Errorf("Score(\"Fb\", \"fooBar\") = %d, want no word-boundary match for the wrong case", score)
+	}
+}
+
+func TestScoreTiers(t *testing.T) {
+	// Each candidate should score strictly higher than the next for "api".
+	ordered := []string{
+		"api",        // exact
+		"api-server", // prefix
+		"legacy-api", // word boundary
+		"rapid",      // substring
+		"a-pi",       // subsequence
+	}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		better := Score("api", ordered[i])
+		worse := Score("api", ordered[i+1])
+		if better <= worse {
+			t.Errorf("Score(api, %q) = %d, want > Score(api, %q) = %d", ordered[i], better, ordered[i+1], worse)
+		}
+	}
+
+	t.Run("shorter prefix match wins", func(t *testing.T) {
+		if Score("kern", "kernelle") <= Score("kern", "kernelle-archive") {
+			t.Error("Expected the shorter prefix match to score higher")
+		}
+	})
+}
+
+func TestMatchRepo(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		identifier string
+		path       string
+		matches    bool
+	}{
+		{name: "owner and repo", query: "acme/api", identifier: "github.com/acme/api", path: "/src/a/api", matches: true},
+		{name: "abbreviated owner", query: "acm/api", identifier: "github.com/acme/api", path: "/src/a/api", matches: true},
+		{name: "host and owner", query: "gitlab.com/acme", identifier: "gitlab.com/acme/api", path: "/src/a/api", matches: true},
+		{name: "wrong owner", query: "globex/api", identifier: "github.com/acme/api", path: "/src/a/api", matches: false},
+		{name: "segments out of order", query: "api/acme", identifier: "github.com/acme/api", path: "/src/a/api", matches: false},
+		{name: "path segments", query: "work/api", identifier: "github.com/acme/api", path: "/home/me/work/api", matches: true},
+		{name: "leading segments need a substring match", query: "gthb/api", identifier: "github.com/acme/api", path: "/src/a/api", matches: false},
+		{name: "plain query matches the name only", query: "acme", identifier: "github.com/acme/api", path: "/src/acme/api", matches: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := MatchRepo(tt.query, "api", tt.identifier, tt.path)
+			if (score > 0) != tt.matches {
+				t.Errorf("MatchRepo(%q) = %d, want match = %v", tt.query, score, tt.matches)
+			}
+		})
+	}
+
+	t.Run("repo name beats a deeper match", func(t *testing.T) {
+		repoMatch := MatchRepo("acme/api", "api", "github.com/acme/api", "/src/api")
+		deeper := MatchRepo("acme/api", "docs", "github.com/acme/api/docs", "/src/docs")
+		if repoMatch <= deeper {
+			t.Errorf("MatchRepo() = %d for acme/api, want > %d for acme/api/docs", repoMatch, deeper)
+		}
+	})
+}
+
+func TestRank(t *testing.T) {
+	repos := []repo.Repo{
+		{Name: "legacy-api", Path: "/src/legacy-api", PinIndex: -1},
+		{Name: "api-gateway", Path: "/src/api-gateway", Pinned: true, PinIndex: 0},
+		{Name: "api", Path: "/src/api", PinIndex: -1},
+		{Name: "api-client", Path: "/src/api-client", PinIndex: -1},
+	}
+
+	Rank(repos, "api", nil)
+
+	expected := []string{"api", "api-gateway", "api-client", "legacy-api"}
+	for i, name := range expected {
+		if repos[i].Name != name {
+			t.Errorf("Rank()[%d] = %q, want %q (got %v)", i, repos[i].Name, name, names(repos))
+		}
+	}
+}
+
+func TestRankKeepsTiers(t *testing.T) {
+	// Boosted, the pinned word-boundary match would outscore the long prefix
+	// match if boosts weren't capped below the prefix tier.
+	pinned := repo.Repo{Name: "my-api", Path: "/src/my-api", Pinned: true, PinIndex: 0}
+	prefix := repo.Repo{Name: "api-" + strings.Repeat("x", 60), Path: "/src/api-long", PinIndex: -1}
+
+	if score := rankScore(pinned, "api", 0); score >= scorePrefix-maxPenalty {
+		t.Errorf("rankScore(pinned boundary match) = %d, want below the prefix tier's floor %d", score, scorePrefix-maxPenalty)
+	}
+
+	repos := []repo.Repo{pinned, prefix}
+	Rank(repos, "api", nil)
+	if repos[0].Path != prefix.Path {
+		t.Errorf("Rank()[0] = %q, want the prefix match first (got %v)", repos[0].Name, names(repos))
+	}
+}
+
+func TestRankWithFrecency(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-frecency-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	usage, err := frecency.LoadFile(filepath.Join(tmpDir, "frecency.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for range 20 {
+		usage.Add("/src/api-client", now)
+	}
+
+	t.Run("frequent repo wins within a tier", func(t *testing.T) {
+		repos := []repo.Repo{
+			{Name: "api-gateway", Path: "/src/api-gateway", PinIndex: -1},
+			{Name: "api-client", Path: "/src/api-client", PinIndex: -1},
+		}
+		Rank(repos, "api", usage)
+		if repos[0].Name != "api-client" {
+			t.Errorf("Rank()[0] = %q, want api-client (got %v)", repos[0].Name, names(repos))
+		}
+	})
+
+	t.Run("frequent repo doesn't beat a better tier", func(t *testing.T) {
+		repos := []repo.Repo{
+			{Name: "my-api-client", Path: "/src/api-client", Pinned: true, PinIndex: 0},
+			{Name: "api-" + strings.Repeat("x", 60), Path: "/src/api-long", PinIndex: -1},
+		}
+		Rank(repos, "api", usage)
+		if repos[0].Path != "/src/api-long" {
+			t.Errorf("Rank()[0] = %q, want the prefix match (got %v)", repos[0].Name, names(repos))
+		}
+	})
+
+	t.Run("frequent repo doesn't beat an exact match", func(t *testing.T) {
+		repos := []repo.Repo{
+			{Name: "api-client", Path: "/src/api-client", Pinned: true, PinIndex: 0},
+			{Name: "api", Path: "/src/api", PinIndex: -1},
+		}
+		Rank(repos, "api", usage)
+		if repos[0].Name != "api" {
+			t.Errorf("Rank()[0] = %q, want api (got %v)", repos[0].Name, names(repos))
+		}
+	})
+}
+
+func TestRankBySegments(t *testing.T) {
+	repos := []repo.Repo{
+		{Name: "api", Identifier: "github.com/globex/api", Path: "/src/globex/api", PinIndex: -1},
+		{Name: "api", Identifier: "github.com/acme/api", Path: "/src/acme/api", PinIndex: -1},
+		{Name: "api-docs", Identifier: "github.com/acme/api-docs", Path: "/src/acme/api-docs", PinIndex: -1},
+	}
+
+	Rank(repos, "acme/api", nil)
+
+	if repos[0].Identifier != "github.com/acme/api" || repos[1].Identifier != "github.com/acme/api-docs" {
+		t.Errorf("Rank() = %v, want acme/api then acme/api-docs first", identifiers(repos))
+	}
+}
+
+func identifiers(repos []repo.Repo) []string {
+	result := make([]string, len(repos))
+	for i, r := range repos {
+		result[i] = r.Identifier
+	}
+	return result
+}
+
+func names(repos []repo.Repo) []string {
+	result := make([]string, len(repos))
+	for i, r := range repos {
+		result[i] = r.Name
+	}
+	return result
+}
@@ -1,14 +1,20 @@
 package search
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 
 	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/frecency"
+	"github.com/kernelle-soft/gimme/internal/ignore"
+	"github.com/kernelle-soft/gimme/internal/index"
 	"github.com/kernelle-soft/gimme/internal/log"
 	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/slice"
 
 	"github.com/go-git/go-git/v5"
 )
@@ -39,83 +45,532 @@ func InFolders(searchFolders []string) RepoSearchOptions {
 	}
 }
 
+// Repositories finds repositories in the search folders that match the query,
+// within the configured search time budget.
 func Repositories(opts RepoSearchOptions) []repo.Repo {
-	found := []repo.Repo{}
-	pins := config.GetPinnedRepos()
+	ctx, cancel := searchContext()
+	defer cancel()
+	return RepositoriesContext(ctx, opts)
+}
+
+// RepositoriesContext is like Repositories but stops discovering when ctx is
+// done, returning whatever was found up to that point.
+func RepositoriesContext(ctx context.Context, opts RepoSearchOptions) []repo.Repo {
+	found, _ := findRepos(ctx, opts.SearchFolders, []string{opts.Query})
+	return found
+}
+
+// RepositoriesWithSubpath resolves a repo/sub/path query. The longest leading
+// run of segments that matches any repository (by name, identifier or path)
+// names the repository; the rest is returned as a subpath inside it.
+func RepositoriesWithSubpath(query string) ([]repo.Repo, string) {
+	ctx, cancel := searchContext()
+	defer cancel()
+
+	segments := splitSegments(query)
+	if len(segments) <= 1 {
+		found, _ := findRepos(ctx, config.GetSearchFolders(), []string{strings.Trim(query, "/")})
+		return found, ""
+	}
+
+	queries := make([]string, len(segments))
+	for i := range segments {
+		queries[i] = strings.Join(segments[:len(segments)-i], "/")
+	}
+
+	found, matched := findRepos(ctx, config.GetSearchFolders(), queries)
+	return found, strings.Join(segments[len(segments)-matched:], "/")
+}
+
+// searchContext applies the configured search time budget.
+func searchContext() (context.Context, context.CancelFunc) {
+	if timeout := config.GetSearchTimeout(); timeout > 0 {
+		return context.WithTimeout(context.Background(), timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// findRepos discovers the repositories in folders and returns the ranked
+// matches of the first query in queries that matches anything, along with the
+// number of queries tried before it.
+func findRepos(ctx context.Context, folders []string, queries []string) ([]repo.Repo, int) {
+	idx := loadIndex()
+
+	entries := discover(ctx, folders, idx, false)
+	matched, tried := firstMatch(entries, queries)
+	if len(matched) == 0 && idx != nil && ctx.Err() == nil {
+		// Cache miss: fall through to a live scan in case the index is missing
+		// something, e.g. a repo that was cloned into an unchanged directory tree.
+		entries = discover(ctx, folders, idx, true)
+		matched, tried = firstMatch(entries, queries)
+	}
+	if ctx.Err() != nil {
+		log.Warning("Repository search stopped early ({}); results may be incomplete.", ctx.Err())
+	}
+	saveIndex(idx)
+
+	found := openRepos(matched, config.GetPinnedRepos())
 
-	for _, folder := range opts.SearchFolders {
-		found = append(found, findReposRecursively(folder, opts.Query, pins)...)
+	query := ""
+	if tried < len(queries) {
+		query = queries[tried]
 	}
+	if query == "" {
+		// Sort alphabetically by name
+		slices.SortFunc(found, func(a, b repo.Repo) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+	} else {
+		// Best match first
+		Rank(found, query, loadUsage())
+	}
+
+	return found, tried
+}
+
+// firstMatch filters entries by each query in turn and returns the matches of
+// the first query that has any, with that query's position in queries.
+func firstMatch(entries []index.Entry, queries []string) ([]index.Entry, int) {
+	for i, query := range queries {
+		if matched := filterEntries(entries, query); len(matched) > 0 {
+			return matched, i
+		}
+	}
+	return nil, len(queries)
+}
 
-	// Sort alphabetically by name
-	slices.SortFunc(found, func(a, b repo.Repo) int {
-		return strings.Compare(a.Name, b.Name)
+// filterEntries keeps the discovered repositories that match query.
+func filterEntries(entries []index.Entry, query string) []index.Entry {
+	return slice.Filter(entries, func(e index.Entry) bool {
+		return MatchRepo(query, e.Name, e.Identifier, e.Path) > 0
 	})
+}
+
+// openRepos opens the discovered repositories, applies pin metadata, and links
+// worktrees to their repositories. Repositories that can no longer be opened
+// are skipped.
+func openRepos(entries []index.Entry, pins []string) []repo.Repo {
+	found := []repo.Repo{}
+	for _, e := range entries {
+		r, ok := openEntry(e, pins)
+		if ok {
+			found = append(found, r)
+		}
+	}
 
+	linkWorktrees(found, entries, pins)
 	return found
 }
 
+// openEntry opens a single discovered repository.
+func openEntry(e index.Entry, pins []string) (repo.Repo, bool) {
+	gitRepo, err := repo.Open(e.Path)
+	if err != nil {
+		log.Debug("Skipping repository \"{}\": {}", e.Path, err)
+		return repo.Repo{}, false
+	}
+
+	pinIndex := slices.Index(pins, e.Path)
+	if pinIndex >= 0 {
+		return repo.NewPinnedRepo(gitRepo, e.Path, e.Name, pinIndex), true
+	}
+	return repo.NewRepo(gitRepo, e.Path, e.Name), true
+}
+
+// loadUsage returns the frecency store, or nil if it can't be read. Ranking
+// still works without it, so failures are only logged.
+func loadUsage() *frecency.Store {
+	usage, err := frecency.Load()
+	if err != nil {
+		log.Debug("Could not load frecency data: {}", err)
+		return nil
+	}
+	return usage
+}
+
 // SortByPins sorts repos with pinned repos first (by pin order), then alphabetically.
 // This is useful for commands like jump that want to prioritize pinned repos.
 func SortByPins(repos []repo.Repo) {
-	slices.SortFunc(repos, func(a, b repo.Repo) int {
-		if a.Pinned && !b.Pinned {
-			return -1
+	slices.SortFunc(repos, comparePins)
+}
+
+// comparePins orders pinned repos first (by pin index), then alphabetically.
+func comparePins(a, b repo.Repo) int {
+	if a.Pinned && !b.Pinned {
+		return -1
+	}
+	if !a.Pinned && b.Pinned {
+		return 1
+	}
+	// Both pinned: sort by pin index (lower index = higher priority)
+	if a.Pinned && b.Pinned {
+		return a.PinIndex - b.PinIndex
+	}
+	// Neither pinned: sort alphabetically
+	return strings.Compare(a.Name, b.Name)
+}
+
+// discovery walks search folders for repositories, reusing index records for
+// directories whose modification time hasn't changed.
+type discovery struct {
+	index       *index.Index // nil disables caching
+	live        bool         // ignore cached records and re-read every directory
+	parallelism int
+
+	mu    sync.Mutex
+	found []index.Entry
+	seen  map[string]string // real path of each directory visited to the path it was visited by
+}
+
+// discover finds every repository under the given search folders, sorted by
+// path. If ctx ends early, the repositories found so far are returned.
+func discover(ctx context.Context, folders []string, idx *index.Index, live bool) []index.Entry {
+	d := &discovery{
+		index:       idx,
+		live:        live,
+		parallelism: config.GetSearchParallelism(),
+		seen:        map[string]string{},
+	}
+	return d.findReposRecursively(ctx, folders)
+}
+
+// findReposRecursively returns the repositories under folders, honoring each
+// search group's walk options and any .gimmeignore files along the way.
+// Search folders themselves are never reported as repositories, only their
+// descendants.
+func (d *discovery) findReposRecursively(ctx context.Context, folders []string) []index.Entry {
+	roots := make([]walkTask, 0, len(folders))
+	for _, folder := range folders {
+		group := config.GetSearchGroup(folder)
+		roots = append(roots, walkTask{
+			dir:   folder,
+			group: &group,
+			rules: (*ignore.Matcher)(nil).Add(folder, group.Exclude),
+		})
+	}
+
+	w := &walker{parallelism: d.parallelism, scan: d.scan}
+	w.walk(ctx, roots)
+
+	// Workers finish in any order; sort so results are deterministic.
+	d.found = d.dropAliases(d.found)
+	slices.SortFunc(d.found, func(a, b index.Entry) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return slices.CompactFunc(d.found, func(a, b index.Entry) bool {
+		return a.Path == b.Path
+	})
+}
+
+// scan records the repositories in a directory and returns the subdirectories
+// to descend into.
+func (d *discovery) scan(task walkTask) []walkTask {
+	group := task.group
+	root := task.depth == 0
+
+	if group.FollowSymlinks && !d.markSeen(task.dir) {
+		return nil
+	}
+
+	info, err := os.Stat(task.dir)
+	if err != nil {
+		log.Error("Error reading directory", "path", task.dir, "error", err)
+		return nil
+	}
+
+	record := d.cached(task.dir, info, root)
+	if record == nil {
+		record = d.read(task.dir, info, root)
+		if record == nil {
+			return nil
 		}
-		if !a.Pinned && b.Pinned {
-			return 1
+	}
+
+	if record.Self != nil {
+		d.report(*record.Self)
+		return nil
+	}
+
+	childDepth := task.depth + 1
+	if group.MaxDepth > 0 && childDepth > group.MaxDepth {
+		return nil
+	}
+
+	rules := task.rules
+	if len(record.Ignore) > 0 {
+		rules = rules.Add(task.dir, record.Ignore)
+	}
+	allowed := func(path string) bool {
+		if group.SkipHidden && strings.HasPrefix(filepath.Base(path), ".") {
+			return false
 		}
-		// Both pinned: sort by pin index (lower index = higher priority)
-		if a.Pinned && b.Pinned {
-			return a.PinIndex - b.PinIndex
+		return !rules.Match(path)
+	}
+
+	for _, r := range record.Repos {
+		if allowed(r.Path) {
+			d.report(r)
 		}
-		// Neither pinned: sort alphabetically
-		return strings.Compare(a.Name, b.Name)
-	})
+	}
+
+	if group.MaxDepth > 0 && childDepth >= group.MaxDepth {
+		return nil
+	}
+
+	subdirs := record.Subdirs
+	if group.FollowSymlinks {
+		subdirs = slices.Concat(subdirs, record.Links)
+	}
+
+	next := []walkTask{}
+	for _, sub := range subdirs {
+		if allowed(sub) {
+			next = append(next, walkTask{dir: sub, depth: childDepth, group: group, rules: rules})
+		}
+	}
+	return next
+}
+
+// report adds a discovered repository to the results.
+func (d *discovery) report(entry index.Entry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.found = append(d.found, entry)
+}
+
+// markSeen records the real path of dir, returning false if it was already
+// visited through another path (e.g. a symlink loop). When a directory is
+// reachable through several paths, the one preferPath picks wins even if it's
+// reached last, so the same path is reported whichever walker gets there
+// first.
+func (d *discovery) markSeen(dir string) bool {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if by, ok := d.seen[real]; ok && !preferPath(dir, by, real) {
+		return false
+	}
+	d.seen[real] = dir
+	return true
+}
+
+// dropAliases removes repositories found through more than one path, keeping
+// the path preferPath picks. Only following symlinks can find them twice.
+func (d *discovery) dropAliases(found []index.Entry) []index.Entry {
+	if len(d.seen) == 0 {
+		return found
+	}
+
+	best := map[string]int{} // real path to index in kept
+	kept := []index.Entry{}
+	for _, entry := range found {
+		real, err := filepath.EvalSymlinks(entry.Path)
+		if err != nil {
+			real = entry.Path
+		}
+		i, ok := best[real]
+		switch {
+		case !ok:
+			best[real] = len(kept)
+			kept = append(kept, entry)
+		case preferPath(entry.Path, kept[i].Path, real):
+			kept[i] = entry
+		}
+	}
+	return kept
+}
+
+// preferPath reports whether path a should be used over b for the directory
+// at real: the path without symlinks first, otherwise the one that sorts
+// first segment by segment.
+func preferPath(a, b, real string) bool {
+	if a == real || b == real {
+		return a == real && b != real
+	}
+	sep := string(filepath.Separator)
+	return slices.Compare(strings.Split(a, sep), strings.Split(b, sep)) < 0
+}
+
+// cached returns the index record for dir if it is still valid.
+func (d *discovery) cached(dir string, info os.FileInfo, root bool) *index.Dir {
+	if d.index == nil || d.live {
+		return nil
+	}
+
+	record, ok := d.index.Get(dir)
+	if !ok || !record.ModTime.Equal(info.ModTime()) {
+		return nil
+	}
+
+	// Turning a directory into a repository (or back) changes its own mtime,
+	// not its parent's, so check the repos we know about are still there.
+	if record.Self != nil && (root || !isRepoDir(dir)) {
+		return nil
+	}
+	for _, r := range record.Repos {
+		if !isRepoDir(r.Path) {
+			return nil
+		}
+	}
+
+	// Editing .gimmeignore in place doesn't touch the directory's mtime.
+	ignoreInfo, err := os.Stat(filepath.Join(dir, ignore.FileName))
+	if err != nil {
+		if record.IgnoreModTime != nil {
+			return nil
+		}
+	} else if record.IgnoreModTime == nil || !record.IgnoreModTime.Equal(ignoreInfo.ModTime()) {
+		return nil
+	}
+
+	return record
 }
 
-func findReposRecursively(folder string, query string, pins []string) []repo.Repo {
-	results := []repo.Repo{}
+// read lists dir and records which children are repositories and which to
+// descend into, along with its .gimmeignore patterns.
+func (d *discovery) read(dir string, info os.FileInfo, root bool) *index.Dir {
+	record := &index.Dir{ModTime: info.ModTime()}
 
-	entries, err := os.ReadDir(folder)
+	if !root {
+		if gitRepo, err := repo.Open(dir); err == nil {
+			record.Self = newEntry(gitRepo, dir)
+			d.put(dir, record)
+			return record
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		log.Error("Error reading directory", "path", folder, "error", err)
-		return results
+		log.Error("Error reading directory", "path", dir, "error", err)
+		return nil
 	}
 
 	for _, entry := range entries {
-		path := filepath.Join(folder, entry.Name())
+		path := filepath.Join(dir, entry.Name())
+
+		if entry.Type()&os.ModeSymlink != 0 {
+			// Only followed when the search group asks for it
+			if target, err := os.Stat(path); err == nil && target.IsDir() {
+				record.Links = append(record.Links, path)
+			}
+			continue
+		}
+
+		if entry.Name() == ignore.FileName {
+			ignoreInfo, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			patterns, err := ignore.ReadFile(path)
+			if err != nil {
+				log.Warning("Error reading \"{}\": {}", path, err)
+				continue
+			}
+			modTime := ignoreInfo.ModTime()
+			record.Ignore = patterns
+			record.IgnoreModTime = &modTime
+			continue
+		}
 
 		if !entry.IsDir() {
 			continue
 		}
 
-		gitRepo, err := git.PlainOpen(path)
+		gitRepo, err := repo.Open(path)
 		if err != nil {
-			results = append(results, findReposRecursively(path, query, pins)...)
+			record.Subdirs = append(record.Subdirs, path)
 			continue
 		}
 
-		if strings.Contains(entry.Name(), query) {
-			pinIndex := slices.Index(pins, path)
-			if pinIndex >= 0 {
-				results = append(results, repo.NewPinnedRepo(gitRepo, path, entry.Name(), pinIndex))
-			} else {
-				results = append(results, repo.NewRepo(gitRepo, path, entry.Name()))
-			}
-		}
+		record.Repos = append(record.Repos, *newEntry(gitRepo, path))
 	}
 
-	return results
+	d.put(dir, record)
+	return record
 }
 
-// FindRepoForPath finds the repository that contains the given path
+func (d *discovery) put(dir string, record *index.Dir) {
+	if d.index != nil {
+		d.index.Put(dir, record)
+	}
+}
+
+// newEntry builds the index entry for an opened repository.
+func newEntry(gitRepo *git.Repository, path string) *index.Entry {
+	r := repo.NewRepo(gitRepo, path, filepath.Base(path))
+	return &index.Entry{
+		Path:       r.Path,
+		Name:       r.Name,
+		Identifier: r.Identifier,
+		Parent:     worktreeParent(path),
+	}
+}
+
+// isRepoDir cheaply checks that path still looks like a repository, either a
+// working tree with a .git entry or a bare repository.
+func isRepoDir(path string) bool {
+	if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(path, "HEAD"))
+	return err == nil
+}
+
+// FindRepoForPath finds the repository that contains the given path by walking
+// up to the enclosing git directory, so repos outside the search groups and
+// linked worktrees are found too. Linked worktrees resolve to their main
+// repository, opened so CurrentBranch reports the worktree's branch. No search
+// group is walked; pin metadata comes straight from the config.
 func FindRepoForPath(path string) *repo.Repo {
-	repos := Repositories(DefaultRepoSearchOptions())
-	for _, r := range repos {
-		if strings.HasPrefix(path, r.Path) {
+	loc, err := repo.Locate(path)
+	if err != nil {
+		return nil
+	}
+
+	gitRepo, err := repo.Open(loc.WorktreeRoot)
+	if err != nil {
+		log.Debug("Could not open repository \"{}\": {}", loc.WorktreeRoot, err)
+		return nil
+	}
+
+	root := loc.MainRoot
+	if root == "" {
+		root = loc.WorktreeRoot
+	}
+
+	var found repo.Repo
+	if pinIndex := resolvedIndex(config.GetPinnedRepos(), root); pinIndex >= 0 {
+		found = repo.NewPinnedRepo(gitRepo, root, filepath.Base(root), pinIndex)
+	} else {
+		found = repo.NewRepo(gitRepo, root, filepath.Base(root))
+	}
+	return &found
+}
+
+// FindByIdentifier finds the repository in the search groups whose identifier
+// is identifier, e.g. "github.com/user/repo". Linked worktrees are skipped in
+// favor of their repository. Returns nil if there's none.
+func FindByIdentifier(identifier string) *repo.Repo {
+	for _, r := range Repositories(DefaultRepoSearchOptions()) {
+		if r.Identifier == identifier && !r.IsWorktree() {
 			return &r
 		}
 	}
 	return nil
 }
+
+// resolvedIndex returns the index of the path in paths that refers to the same
+// directory as target once symlinks are resolved, or -1.
+func resolvedIndex(paths []string, target string) int {
+	return slices.IndexFunc(paths, func(p string) bool {
+		if p == target {
+			return true
+		}
+		resolved, err := filepath.EvalSymlinks(p)
+		return err == nil && resolved == target
+	})
+}
@@ -0,0 +1,262 @@
+package search
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/kernelle-soft/gimme/internal/index"
+	"github.com/spf13/viper"
+)
+
+// initRepo creates a git repository at path, including missing parents.
+func initRepo(t *testing.T, path string) {
+	t.Helper()
+	if _, err := git.PlainInit(path, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func entryPaths(entries []index.Entry) []string {
+	paths := make([]string, len(entries))
+	for i, e := range entries {
+		paths[i] = e.Path
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+func TestDiscoverWithIndex(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-discover-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	root := filepath.Join(tmpDir, "src")
+	initRepo(t, filepath.Join(root, "alpha"))
+	initRepo(t, filepath.Join(root, "org", "beta"))
+	if err := os.MkdirAll(filepath.Join(root, "org", "plain"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := index.LoadFile(filepath.Join(tmpDir, "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := entryPaths(discover(context.Background(), []string{root}, idx, false))
+	expected := []string{filepath.Join(root, "alpha"), filepath.Join(root, "org", "beta")}
+	if !slices.Equal(found, expected) {
+		t.Fatalf("discover() = %v, want %v", found, expected)
+	}
+
+	if err := idx.Save(); err != nil {
+		t.Fatal(err)
+	}
+	idx, err = index.LoadFile(idx.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("cached records are reused", func(t *testing.T) {
+		if _, ok := idx.Get(filepath.Join(root, "org")); !ok {
+			t.Fatal("Expected org directory to be indexed")
+		}
+		found := entryPaths(discover(context.Background(), []string{root}, idx, false))
+		if !slices.Equal(found, expected) {
+			t.Errorf("discover() = %v, want %v", found, expected)
+		}
+	})
+
+	t.Run("new repo in a known directory is found", func(t *testing.T) {
+		initRepo(t, filepath.Join(root, "org", "gamma"))
+		found := entryPaths(discover(context.Background(), []string{root}, idx, false))
+		if !slices.Contains(found, filepath.Join(root, "org", "gamma")) {
+			t.Errorf("discover() = %v, want it to include gamma", found)
+		}
+	})
+
+	t.Run("directory turned into a repo is found", func(t *testing.T) {
+		plain := filepath.Join(root, "org", "plain")
+		initRepo(t, plain)
+		found := entryPaths(discover(context.Background(), []string{root}, idx, false))
+		if !slices.Contains(found, plain) {
+			t.Errorf("discover() = %v, want it to include %s", found, plain)
+		}
+	})
+
+	t.Run("removed repo is dropped", func(t *testing.T) {
+		if err := os.RemoveAll(filepath.Join(root, "alpha")); err != nil {
+			t.Fatal(err)
+		}
+		found := entryPaths(discover(context.Background(), []string{root}, idx, false))
+		if slices.Contains(found, filepath.Join(root, "alpha")) {
+			t.Errorf("discover() = %v, want alpha to be gone", found)
+		}
+	})
+}
+
+func TestDiscoverHonorsGroupOptions(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-discover-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	root := filepath.Join(tmpDir, "src")
+	elsewhere := filepath.Join(tmpDir, "elsewhere")
+	for _, p := range []string{
+		"a",
+		"mid/m",
+		"deep/er/z",
+		"node_modules/x",
+		".hidden/h",
+		"by-file/q",
+	} {
+		initRepo(t, filepath.Join(root, p))
+	}
+	initRepo(t, filepath.Join(elsewhere, "l"))
+
+	if err := os.WriteFile(filepath.Join(root, ".gimmeignore"), []byte("by-file\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(elsewhere, filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	// A loop back to the search folder must not be walked forever
+	if err := os.Symlink(root, filepath.Join(elsewhere, "back")); err != nil {
+		t.Fatal(err)
+	}
+
+	viper.Set("search-folders", []any{map[string]any{
+		"path":            root,
+		"max-depth":       2,
+		"exclude":         []any{"node_modules"},
+		"skip-hidden":     true,
+		"follow-symlinks": true,
+	}})
+	defer viper.Reset()
+
+	found := entryPaths(discover(context.Background(), []string{root}, nil, false))
+	expected := []string{
+		filepath.Join(root, "a"),
+		filepath.Join(root, "link", "l"),
+		filepath.Join(root, "mid", "m"),
+	}
+	if !slices.Equal(found, expected) {
+		t.Errorf("discover() = %v, want %v", found, expected)
+	}
+
+	t.Run("defaults don't follow symlinks or limit depth", func(t *testing.T) {
+		viper.Reset()
+		found := entryPaths(discover(context.Background(), []string{root}, nil, false))
+		if slices.Contains(found, filepath.Join(root, "link", "l")) {
+			t.Errorf("discover() = %v, want symlinks not followed", found)
+		}
+		if !slices.Contains(found, filepath.Join(root, "deep", "er", "z")) {
+			t.Errorf("discover() = %v, want deep repo found", found)
+		}
+		if slices.Contains(found, filepath.Join(root, "by-file", "q")) {
+			t.Errorf("discover() = %v, want .gimmeignore honored", found)
+		}
+	})
+}
+
+func TestDiscoverPrefersRealPaths(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-discover-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+
+	root := filepath.Join(tmpDir, "src")
+	elsewhere := filepath.Join(tmpDir, "elsewhere")
+	initRepo(t, filepath.Join(root, "real", "r"))
+	initRepo(t, filepath.Join(elsewhere, "e"))
+	for link, target := range map[string]string{
+		"a-real": filepath.Join(root, "real"),
+		"z-real": filepath.Join(root, "real"),
+		"b-else": elsewhere,
+		"c-else": elsewhere,
+	} {
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	viper.Set("search-folders", []any{map[string]any{"path": root, "follow-symlinks": true}})
+	viper.Set("search.parallelism", 8)
+	defer viper.Reset()
+
+	// The repository inside the search folder is reported by its real path,
+	// the one outside it through the first link, however the walkers race.
+	expected := []string{
+		filepath.Join(root, "b-else", "e"),
+		filepath.Join(root, "real", "r"),
+	}
+	for range 20 {
+		found := entryPaths(discover(context.Background(), []string{root}, nil, false))
+		if !slices.Equal(found, expected) {
+			t.Fatalf("discover() = %v, want %v", found, expected)
+		}
+	}
+}
+
+func TestFindRepoForPath(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-find-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+
+	mainPath := filepath.Join(tmpDir, "foo")
+	initRepo(t, mainPath)
+	initRepo(t, filepath.Join(tmpDir, "foobar"))
+	runGit(t, mainPath, "commit", "--allow-empty", "-m", "initial")
+
+	t.Run("shared prefix doesn't match", func(t *testing.T) {
+		found := FindRepoForPath(filepath.Join(tmpDir, "foobar"))
+		if found == nil || found.Path != filepath.Join(tmpDir, "foobar") {
+			t.Errorf("FindRepoForPath() = %v, want foobar", found)
+		}
+	})
+
+	t.Run("linked worktree resolves to its main repository", func(t *testing.T) {
+		wtDir := filepath.Join(tmpDir, "foo.worktrees", "feature")
+		runGit(t, mainPath, "worktree", "add", "-b", "feature", wtDir)
+
+		found := FindRepoForPath(wtDir)
+		if found == nil {
+			t.Fatal("Expected a repository for the worktree")
+		}
+		if found.Path != mainPath || found.Name != "foo" {
+			t.Errorf("FindRepoForPath() = %q (%q), want %q", found.Path, found.Name, mainPath)
+		}
+		if branch := found.CurrentBranch(); branch != "feature" {
+			t.Errorf("CurrentBranch() = %q, want the worktree's branch", branch)
+		}
+	})
+
+	t.Run("outside any repository", func(t *testing.T) {
+		if found := FindRepoForPath(tmpDir); found != nil {
+			t.Errorf("FindRepoForPath() = %q, want nil", found.Path)
+		}
+	})
+}
+
+// runGit runs a git command in dir, failing the test on error.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
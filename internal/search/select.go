@@ -0,0 +1,165 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/parallel"
+	"github.com/kernelle-soft/gimme/internal/path"
+	"github.com/kernelle-soft/gimme/internal/repo"
+)
+
+// Selector picks repositories by a --select expression. An expression is
+// terms separated by spaces, all of which must hold, and each term is
+// alternatives separated by commas, any of which may hold. An alternative is
+// one of:
+//
+//	tag:<tag>       tagged with tag, by 'gimme config add tag' or in .gimme.yaml
+//	group:<group>   in a search group, by path or index
+//	pinned          pinned
+//	dirty           has uncommitted changes or an operation in progress
+//	<glob>          identifier or name matches glob, e.g. github.com/acme/*
+//
+// and ! in front negates it. For example, "tag:backend,tag:infra !dirty"
+// selects the clean repositories tagged backend or infra.
+type Selector struct {
+	terms [][]selectorAtom
+}
+
+// selectorAtom is a single alternative of a term.
+type selectorAtom struct {
+	negate bool
+	kind   string // "tag", "group", "pinned", "dirty" or "glob"
+	value  string // the tag, group path or glob
+}
+
+// ParseSelector parses a --select expression. Groups are resolved and globs
+// checked as it's parsed, so mistakes are reported before anything runs.
+func ParseSelector(expr string) (*Selector, error) {
+	s := &Selector{}
+	for _, term := range strings.Fields(expr) {
+		atoms := []selectorAtom{}
+		for _, alternative := range strings.Split(term, ",") {
+			atom, err := parseSelectorAtom(alternative)
+			if err != nil {
+				return nil, err
+			}
+			atoms = append(atoms, atom)
+		}
+		s.terms = append(s.terms, atoms)
+	}
+	return s, nil
+}
+
+func parseSelectorAtom(alternative string) (selectorAtom, error) {
+	atom := selectorAtom{}
+	alternative, atom.negate = strings.CutPrefix(alternative, "!")
+	if alternative == "" {
+		return atom, errors.New("empty alternative in selector")
+	}
+
+	switch key, value, ok := strings.Cut(alternative, ":"); {
+	case alternative == "pinned" || alternative == "dirty":
+		atom.kind = alternative
+	case ok && key == "tag":
+		if value == "" {
+			return atom, fmt.Errorf("%q names no tag", alternative)
+		}
+		atom.kind, atom.value = "tag", value
+	case ok && key == "group":
+		group, err := ResolveGroup(value)
+		if err != nil {
+			return atom, err
+		}
+		atom.kind, atom.value = "group", group
+	case ok && !strings.ContainsAny(key, "./*?["):
+		return atom, fmt.Errorf("unknown selector %q; use tag:, group:, pinned, dirty or a glob", key+":")
+	default:
+		if _, err := filepath.Match(alternative, ""); err != nil {
+			return atom, fmt.Errorf("invalid glob %q: %w", alternative, err)
+		}
+		atom.kind, atom.value = "glob", alternative
+	}
+	return atom, nil
+}
+
+// Match reports whether the selector picks r. An empty selector picks every
+// repository. The working tree is only read when a term asks about it.
+func (s *Selector) Match(r repo.Repo) bool {
+	var dirty *bool
+	isDirty := func() bool {
+		if dirty == nil {
+			status, err := r.Status()
+			d := err == nil && (status.IsDirty() || status.Operation != "")
+			dirty = &d
+		}
+		return *dirty
+	}
+
+	for _, term := range s.terms {
+		if !slices.ContainsFunc(term, func(atom selectorAtom) bool {
+			return atom.matches(r, isDirty) != atom.negate
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+func (atom selectorAtom) matches(r repo.Repo, isDirty func() bool) bool {
+	switch atom.kind {
+	case "tag":
+		return slices.Contains(SettingsFor(r.Identifier, r.Path).Tags, atom.value)
+	case "group":
+		return path.IsWithin(r.Path, atom.value)
+	case "pinned":
+		return r.Pinned
+	case "dirty":
+		return isDirty()
+	default:
+		byIdentifier, _ := filepath.Match(atom.value, r.Identifier)
+		byName, _ := filepath.Match(atom.value, r.Name)
+		return byIdentifier || byName
+	}
+}
+
+// Filter returns the repositories in repos the selector picks, in order,
+// checking up to n at once.
+func (s *Selector) Filter(repos []repo.Repo, n int) []repo.Repo {
+	picked := parallel.Map(repos, n, s.Match)
+	selected := []repo.Repo{}
+	for i, r := range repos {
+		if picked[i] {
+			selected = append(selected, r)
+		}
+	}
+	return selected
+}
+
+// ResolveGroup resolves a search group given by path or by its index in
+// 'gimme config ls group'.
+func ResolveGroup(group string) (string, error) {
+	folders := config.GetSearchFolders()
+	if i, err := strconv.Atoi(group); err == nil {
+		if i < 0 || i >= len(folders) {
+			return "", fmt.Errorf("no search group %d (have %d)", i, len(folders))
+		}
+		return folders[i], nil
+	}
+
+	normalized, _ := path.Normalize(group)
+	if abs, err := filepath.Abs(normalized); err == nil {
+		normalized = abs
+	}
+	for _, folder := range folders {
+		if folder == normalized {
+			return folder, nil
+		}
+	}
+	return "", fmt.Errorf("no search group %q", group)
+}
@@ -0,0 +1,78 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/spf13/viper"
+)
+
+func TestSelector(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-select-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	defer viper.Reset()
+
+	work, oss := filepath.Join(tmpDir, "work"), filepath.Join(tmpDir, "oss")
+	viper.Set("search-folders", []string{work, oss})
+	viper.Set("repositories", map[string]any{
+		"github.com/acme/api":   map[string]any{"tags": []string{"backend"}},
+		"github.com/acme/infra": map[string]any{"tags": []string{"infra", "backend"}},
+	})
+
+	repos := []repo.Repo{
+		{Name: "api", Identifier: "github.com/acme/api", Path: filepath.Join(work, "api"), Pinned: true},
+		{Name: "infra", Identifier: "github.com/acme/infra", Path: filepath.Join(work, "infra")},
+		{Name: "web", Identifier: "github.com/acme/web", Path: filepath.Join(work, "web")},
+		{Name: "gimme", Identifier: "github.com/kernelle-soft/gimme", Path: filepath.Join(oss, "gimme")},
+	}
+	for _, r := range repos {
+		initRepo(t, r.Path)
+	}
+	if err := os.WriteFile(filepath.Join(work, "web", "new.txt"), []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{"", []string{"api", "infra", "web", "gimme"}},
+		{"tag:backend", []string{"api", "infra"}},
+		{"tag:infra,pinned", []string{"api", "infra"}},
+		{"tag:backend !tag:infra", []string{"api"}},
+		{"group:1", []string{"gimme"}},
+		{"group:" + work + " !pinned", []string{"infra", "web"}},
+		{"dirty", []string{"web"}},
+		{"!dirty github.com/acme/*", []string{"api", "infra"}},
+		{"g*", []string{"gimme"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			selector, err := ParseSelector(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if selected := names(selector.Filter(repos, 2)); !slices.Equal(selected, tt.expected) {
+				t.Errorf("select %q = %v, want %v", tt.expr, selected, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseSelectorErrors(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("search-folders", []string{"/src"})
+
+	for _, expr := range []string{"tag:", "owner:acme", "group:3", "group:/elsewhere", "tag:a,", "[bad"} {
+		if _, err := ParseSelector(expr); err == nil {
+			t.Errorf("ParseSelector(%q) should fail", expr)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package search
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/log"
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/trust"
+)
+
+// Settings are a repository's settings: the user's, from the global config,
+// merged with those the repository commits in its .gimme.yaml.
+type Settings struct {
+	Protected []string // never cleaned: the global protected branches and the repository's
+	Pinned    []string // branches the user pinned in the repository
+	Entry     string   // subdirectory jumps land in, "" for the root
+	Tags      []string
+	OnEnter   string            // shell command run after jumping to the repository
+	Env       map[string]string // environment set after jumping to the repository
+	Tasks     map[string]string // commands 'gimme task' runs by name
+
+	// Untrusted is set when the repository's on-enter command and env were
+	// left out because its .gimme.yaml hasn't been trusted with 'gimme trust'.
+	Untrusted bool
+}
+
+// localConfigs caches .gimme.yaml files by working tree root, so a broken one
+// is only warned about once.
+var localConfigs sync.Map
+
+// SettingsFor returns the settings of the repository with identifier whose
+// working tree is at root. Where both set a value, the user's entry and
+// on-enter command win over the repository's; lists are combined.
+//
+// Anyone who can commit to the repository can change its .gimme.yaml, so its
+// on-enter command and env, which run in and change the user's shell, are
+// only used once the user has trusted the file as it is now.
+func SettingsFor(identifier, root string) Settings {
+	local := loadLocalConfig(root)
+	untrusted := (local.OnEnter != "" || len(local.Env) > 0) &&
+		!trust.IsTrusted(filepath.Join(root, repo.LocalConfigFile), local.Hash)
+	if untrusted {
+		local.OnEnter, local.Env = "", nil
+	}
+
+	s := Settings{
+		Protected: union(config.GetGlobalPinnedBranches(), local.Protected),
+		Pinned:    config.GetRepoPinnedBranches()[identifier],
+		Entry:     config.GetRepoEntry(identifier),
+		Tags:      union(config.GetTagsForRepo(identifier), local.Tags),
+		OnEnter:   config.GetRepoOnEnter(identifier),
+		Env:       maps.Clone(local.Env),
+		Tasks:     maps.Clone(local.Tasks),
+		Untrusted: untrusted,
+	}
+	if s.Entry == "" {
+		s.Entry = local.Entry
+	}
+	if s.OnEnter == "" {
+		s.OnEnter = local.OnEnter
+	}
+	return s
+}
+
+// loadLocalConfig reads the .gimme.yaml of the working tree at root, warning
+// about and ignoring one that can't be read and an entry outside the tree.
+func loadLocalConfig(root string) repo.LocalConfig {
+	if cached, ok := localConfigs.Load(root); ok {
+		return cached.(repo.LocalConfig)
+	}
+
+	local, err := repo.LoadLocalConfig(root)
+	if err != nil {
+		log.Warning("Ignoring \"{}\": {}", root, err)
+	}
+	if local.Entry != "" && !filepath.IsLocal(local.Entry) {
+		log.Warning("Ignoring entry \"{}\" in {}: it's outside the repository.", local.Entry, repo.LocalConfigFile)
+		local.Entry = ""
+	}
+	localConfigs.Store(root, local)
+	return local
+}
+
+// union returns the strings in a followed by those in b that a doesn't have.
+func union(a, b []string) []string {
+	result := slices.Clone(a)
+	for _, s := range b {
+		if !slices.Contains(result, s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
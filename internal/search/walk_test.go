@@ -0,0 +1,92 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// fakeTree describes a directory tree as parent → children.
+func fakeTree(width, depth int) map[string][]string {
+	tree := map[string][]string{}
+	var build func(dir string, level int)
+	build = func(dir string, level int) {
+		if level == depth {
+			return
+		}
+		for i := range width {
+			child := fmt.Sprintf("%s/%d", dir, i)
+			tree[dir] = append(tree[dir], child)
+			build(child, level+1)
+		}
+	}
+	build("root", 0)
+	return tree
+}
+
+// children returns the tasks for a directory's children in a fake tree.
+func children(tree map[string][]string, task walkTask) []walkTask {
+	next := []walkTask{}
+	for _, child := range tree[task.dir] {
+		next = append(next, walkTask{dir: child, depth: task.depth + 1})
+	}
+	return next
+}
+
+func TestWalkerVisitsEveryDirectory(t *testing.T) {
+	tree := fakeTree(4, 4)
+
+	for _, parallelism := range []int{1, 8} {
+		t.Run(fmt.Sprintf("parallelism %d", parallelism), func(t *testing.T) {
+			var mu sync.Mutex
+			visited := []string{}
+
+			w := &walker{
+				parallelism: parallelism,
+				scan: func(task walkTask) []walkTask {
+					mu.Lock()
+					visited = append(visited, task.dir)
+					mu.Unlock()
+					return children(tree, task)
+				},
+			}
+			w.walk(context.Background(), []walkTask{{dir: "root"}})
+
+			// 1 + 4 + 16 + 64 + 256 directories
+			if len(visited) != 341 {
+				t.Errorf("Visited %d directories, want 341", len(visited))
+			}
+			slices.Sort(visited)
+			if len(slices.Compact(visited)) != len(visited) {
+				t.Error("Expected each directory to be visited once")
+			}
+		})
+	}
+}
+
+func TestWalkerStopsWhenCancelled(t *testing.T) {
+	tree := fakeTree(4, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	visited := 0
+	w := &walker{
+		parallelism: 2,
+		scan: func(task walkTask) []walkTask {
+			mu.Lock()
+			defer mu.Unlock()
+			visited++
+			if visited == 10 {
+				cancel()
+			}
+			return children(tree, task)
+		},
+	}
+	w.walk(ctx, []walkTask{{dir: "root"}})
+
+	if visited >= 341 {
+		t.Errorf("Visited %d directories, want the walk to stop early", visited)
+	}
+}
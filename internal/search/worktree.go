@@ -1 +1,68 @@
-package search
\ No newline at end of file
+package search
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/kernelle-soft/gimme/internal/index"
+	"github.com/kernelle-soft/gimme/internal/repo"
+)
+
+// worktreeParent returns the main repository path of the linked worktree at
+// path, or "" if path isn't a linked worktree.
+func worktreeParent(path string) string {
+	info, err := os.Stat(filepath.Join(path, ".git"))
+	if err != nil || info.IsDir() {
+		return ""
+	}
+
+	loc, err := repo.Locate(path)
+	if err != nil || !loc.Linked {
+		return ""
+	}
+	return loc.MainRoot
+}
+
+// linkWorktrees points each linked worktree in found at its repository. The
+// repository is taken from found when it's there, and opened otherwise.
+func linkWorktrees(found []repo.Repo, entries []index.Entry, pins []string) {
+	parents := map[string]string{}
+	for _, e := range entries {
+		if e.Parent != "" {
+			parents[e.Path] = e.Parent
+		}
+	}
+	if len(parents) == 0 {
+		return
+	}
+
+	byPath := map[string]*repo.Repo{}
+	for i := range found {
+		byPath[found[i].Path] = &found[i]
+		if resolved, err := filepath.EvalSymlinks(found[i].Path); err == nil {
+			byPath[resolved] = &found[i]
+		}
+	}
+
+	for i := range found {
+		parentPath, ok := parents[found[i].Path]
+		if !ok {
+			continue
+		}
+
+		parent, ok := byPath[parentPath]
+		if !ok {
+			opened, ok := openEntry(index.Entry{Path: parentPath, Name: filepath.Base(parentPath)}, pins)
+			if !ok {
+				continue
+			}
+			parent = &opened
+			byPath[parentPath] = parent
+		}
+
+		// Copy so the link doesn't alias an element of found, which callers
+		// are free to sort.
+		link := *parent
+		found[i].Parent = &link
+	}
+}
@@ -0,0 +1,55 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kernelle-soft/gimme/internal/index"
+)
+
+func TestDiscoverLinksWorktrees(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-worktree-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+
+	root := filepath.Join(tmpDir, "src")
+	mainPath := filepath.Join(root, "api")
+	wtPath := filepath.Join(root, "api.worktrees", "feature")
+	initRepo(t, mainPath)
+	runGit(t, mainPath, "commit", "--allow-empty", "-m", "initial")
+	runGit(t, mainPath, "worktree", "add", "-b", "feature", wtPath)
+
+	idx, err := index.LoadFile(filepath.Join(tmpDir, "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := discover(context.Background(), []string{root}, idx, false)
+	found := openRepos(entries, nil)
+	if len(found) != 2 {
+		t.Fatalf("openRepos() = %v, want the repository and its worktree", names(found))
+	}
+
+	for _, r := range found {
+		switch r.Path {
+		case mainPath:
+			if r.IsWorktree() {
+				t.Error("Expected the main repository not to be a worktree")
+			}
+		case wtPath:
+			if !r.IsWorktree() || r.Parent.Path != mainPath {
+				t.Errorf("Expected %s to be a worktree of %s, got parent %v", wtPath, mainPath, r.Parent)
+			}
+			if branch := r.CurrentBranch(); branch != "feature" {
+				t.Errorf("CurrentBranch() = %q, want feature", branch)
+			}
+		default:
+			t.Errorf("Unexpected repository %s", r.Path)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Package shell generates the shell functions that wrap the gimme binary. The
+// binary can't change its parent shell's directory or environment, so it
+// prints directives on stdout (see package directive) and the wrapper carries
+// them out.
+package shell
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// DefaultName is the name the wrapper function gets unless told otherwise.
+const DefaultName = "gimme"
+
+var ErrUnsupportedShell = errors.New("unsupported shell")
+
+// scripts maps each supported shell to its template.
+var scripts = map[string]string{
+	"bash":   "templates/bash.sh",
+	"zsh":    "templates/zsh.zsh",
+	"fish":   "templates/fish.fish",
+	"nu":     "templates/nu.nu",
+	"elvish": "templates/elvish.elv",
+}
+
+// namePattern keeps function names to what every supported shell accepts.
+var namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// Options customize the generated script.
+type Options struct {
+	Name string // name of the wrapper function
+}
+
+// Shells returns the supported shells in alphabetical order.
+func Shells() []string {
+	return slices.Sorted(maps.Keys(scripts))
+}
+
+// Script returns the integration script for shell.
+func Script(shell string, opts Options) (string, error) {
+	file, ok := scripts[shell]
+	if !ok {
+		return "", fmt.Errorf("%w %q (supported: %s)", ErrUnsupportedShell, shell, strings.Join(Shells(), ", "))
+	}
+
+	if opts.Name == "" {
+		opts.Name = DefaultName
+	}
+	if !namePattern.MatchString(opts.Name) {
+		return "", fmt.Errorf("invalid function name %q", opts.Name)
+	}
+
+	tmpl, err := template.ParseFS(templates, file)
+	if err != nil {
+		return "", err
+	}
+
+	var script strings.Builder
+	if err := tmpl.Execute(&script, opts); err != nil {
+		return "", err
+	}
+	return script.String(), nil
+}
@@ -0,0 +1,164 @@
+package shell
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScript(t *testing.T) {
+	for _, sh := range Shells() {
+		t.Run(sh, func(t *testing.T) {
+			script, err := Script(sh, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{"gimme", "cd://", "#gimme:1", "GIMME_SESSION", "GIMME_PROTOCOL"} {
+				if !strings.Contains(script, want) {
+					t.Errorf("Script(%q) doesn't mention %q", sh, want)
+				}
+			}
+			if strings.Contains(script, "{{") {
+				t.Errorf("Script(%q) has unexpanded template actions", sh)
+			}
+		})
+	}
+
+	t.Run("custom name", func(t *testing.T) {
+		tests := map[string]string{
+			"bash":   "g() {",
+			"zsh":    "g() {",
+			"fish":   "function g ",
+			"nu":     "def --env --wrapped g ",
+			"elvish": "fn g {",
+		}
+		for sh, want := range tests {
+			script, err := Script(sh, Options{Name: "g"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(script, want) {
+				t.Errorf("Script(%q, g) doesn't define %q", sh, want)
+			}
+			if !strings.Contains(script, "command gimme") && !strings.Contains(script, "^gimme") && !strings.Contains(script, "e:gimme") {
+				t.Errorf("Script(%q, g) should still call the gimme binary", sh)
+			}
+		}
+	})
+
+	t.Run("unsupported shell", func(t *testing.T) {
+		if _, err := Script("tcsh", Options{}); !errors.Is(err, ErrUnsupportedShell) {
+			t.Errorf("Script(tcsh) error = %v, want ErrUnsupportedShell", err)
+		}
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		if _, err := Script("bash", Options{Name: "g; rm -rf ~"}); err == nil {
+			t.Error("Expected an invalid function name to be rejected")
+		}
+	})
+}
+
+// TestPosixWrappers runs the bash and zsh wrappers against a fake gimme binary,
+// for whichever of those shells are installed.
+func TestPosixWrappers(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-shell-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// The fake binary prints its first argument as a cd directive when it's a
+	// directory, echoes the session, and otherwise exits with the argument.
+	// "directives <dir>" prints a full set of directives if the wrapper speaks
+	// the protocol.
+	binDir := filepath.Join(tmpDir, "bin")
+	if err := os.Mkdir(binDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	fake := `#!/bin/sh
+if [ "$1" = session ]; then printf '%s\n' "$GIMME_SESSION"; exit 0; fi
+if [ "$1" = directives ] && [ "$GIMME_PROTOCOL" = 1 ]; then
+  printf '#gimme:1\ncd %s\nsetenv GIMME_REPO a\\\\b\\nc\nunsetenv OLD\nrun RAN=yes\nmessage hi\nunknown x\n' "$2"
+  exit 0
+fi
+if [ -d "$1" ]; then printf 'cd://%s\n' "$1"; exit 0; fi
+echo "not a directory" >&2
+exit "$1"
+`
+	if err := os.WriteFile(filepath.Join(binDir, "gimme"), []byte(fake), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	spaced := filepath.Join(tmpDir, "with space")
+	newline := filepath.Join(tmpDir, "with\nnewline\n")
+	for _, dir := range []string{spaced, newline} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, sh := range []string{"bash", "zsh"} {
+		t.Run(sh, func(t *testing.T) {
+			shellPath, err := exec.LookPath(sh)
+			if err != nil {
+				t.Skipf("%s not installed", sh)
+			}
+
+			script, err := Script(sh, Options{Name: "g"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			run := func(t *testing.T, body string) string {
+				t.Helper()
+				cmd := exec.Command(shellPath, "-c", script+"\n"+body)
+				cmd.Env = append(os.Environ(), "PATH="+binDir+":"+os.Getenv("PATH"), "GIMME_SESSION=")
+				out, err := cmd.Output()
+				if err != nil {
+					t.Fatalf("%s failed: %v", sh, err)
+				}
+				return string(out)
+			}
+
+			t.Run("path with spaces", func(t *testing.T) {
+				out := run(t, `g '`+spaced+`' && pwd`)
+				if strings.TrimSuffix(out, "\n") != spaced {
+					t.Errorf("pwd = %q, want %q", out, spaced)
+				}
+			})
+
+			t.Run("path with newlines", func(t *testing.T) {
+				out := run(t, `g '`+newline+`' && printf '%s' "$PWD"`)
+				if out != newline {
+					t.Errorf("PWD = %q, want %q", out, newline)
+				}
+			})
+
+			t.Run("exit code", func(t *testing.T) {
+				out := run(t, `g 3; echo "rc=$?"`)
+				if !strings.Contains(out, "rc=3") {
+					t.Errorf("output = %q, want rc=3", out)
+				}
+			})
+
+			t.Run("directives", func(t *testing.T) {
+				out := run(t, `OLD=1; g directives '`+spaced+`'; printf '%s|%s|%s|%s' "$PWD" "$GIMME_REPO" "${OLD-unset}" "$RAN"`)
+				want := spaced + "|a\\b\nc|unset|yes"
+				if out != want {
+					t.Errorf("output = %q, want %q", out, want)
+				}
+			})
+
+			t.Run("session", func(t *testing.T) {
+				out := run(t, `g session`)
+				if strings.TrimSpace(out) == "" {
+					t.Error("Expected the wrapper to set GIMME_SESSION")
+				}
+			})
+		})
+	}
+}
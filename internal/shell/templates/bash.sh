@@ -0,0 +1,57 @@
+# gimme shell integration for bash. Load it from ~/.bashrc with:
+#
+#   eval "$(gimme init bash)"
+
+{{.Name}}() {
+    local output exit_code line kind arg name
+    # GIMME_PROTOCOL tells gimme this wrapper understands "#gimme:1" directives
+    # (see below). Older wrappers only get a single "cd://<path>" line. The
+    # trailing "." stops command substitution from stripping newlines that are
+    # part of the output.
+    output="$(GIMME_PROTOCOL=1 GIMME_SESSION="${GIMME_SESSION:-$$}" command gimme "$@"; exit_code=$?; printf .; exit "$exit_code")"
+    exit_code=$?
+    output="${output%.}"
+
+    if [[ "$output" == cd://* ]]; then
+        output="${output#cd://}"
+        builtin cd -- "${output%$'\n'}"
+        return
+    fi
+
+    # One directive per line: "<kind> <argument>", where the argument escapes
+    # backslashes as \\ and newlines as \n. Unknown directives are ignored.
+    if [[ "$output" == "#gimme:1"$'\n'* ]]; then
+        while IFS= read -r line; do
+            kind="${line%% *}"
+            arg="${line#"$kind"}"
+            arg="${arg# }"
+            case "$kind" in
+                cd)
+                    printf -v arg '%b' "$arg"
+                    builtin cd -- "$arg" || exit_code=$?
+                    ;;
+                setenv)
+                    name="${arg%% *}"
+                    arg="${arg#"$name"}"
+                    printf -v arg '%b' "${arg# }"
+                    export "$name=$arg"
+                    ;;
+                unsetenv)
+                    unset "$arg"
+                    ;;
+                run)
+                    printf -v arg '%b' "$arg"
+                    eval "$arg" || exit_code=$?
+                    ;;
+                message)
+                    printf -v arg '%b' "$arg"
+                    printf '%s\n' "$arg" >&2
+                    ;;
+            esac
+        done <<< "${output#*$'\n'}"
+        return "$exit_code"
+    fi
+
+    printf '%s' "$output"
+    return "$exit_code"
+}
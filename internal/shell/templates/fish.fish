@@ -0,0 +1,47 @@
+# gimme shell integration for fish. Load it from ~/.config/fish/config.fish with:
+#
+#   gimme init fish | source
+
+function {{.Name}} --wraps gimme --description 'Jump between repositories with gimme'
+    set -l session $fish_pid
+    set -q GIMME_SESSION; and set session $GIMME_SESSION
+
+    # GIMME_PROTOCOL tells gimme this wrapper understands "#gimme:1" directives
+    # (see below). Older wrappers only get a single "cd://<path>" line.
+    # Command substitution splits output into lines, so join them back up to
+    # keep newlines that are part of the path.
+    set -l lines (GIMME_PROTOCOL=1 GIMME_SESSION=$session command gimme $argv)
+    set -l exit_code $status
+    set -l output (string join \n -- $lines | string collect)
+
+    if string match -q -- 'cd://*' "$output"
+        builtin cd (string sub --start 6 -- "$output" | string collect)
+        return
+    end
+
+    # One directive per line: "<kind> <argument>", where the argument escapes
+    # backslashes as \\ and newlines as \n. Unknown directives are ignored.
+    if test "$lines[1]" = '#gimme:1'
+        for line in $lines[2..-1]
+            set -l parts (string split --max 1 ' ' -- $line)
+            set -l arg (printf '%b' "$parts[2]" | string collect --no-trim-newlines)
+            switch $parts[1]
+                case cd
+                    builtin cd $arg; or set exit_code $status
+                case setenv
+                    set -l setting (string split --max 1 ' ' -- $parts[2])
+                    set -gx $setting[1] (printf '%b' "$setting[2]" | string collect --no-trim-newlines)
+                case unsetenv
+                    set -e $parts[2]
+                case run
+                    eval $arg; or set exit_code $status
+                case message
+                    printf '%s\n' $arg >&2
+            end
+        end
+        return $exit_code
+    end
+
+    test -n "$output"; and printf '%s\n' $lines
+    return $exit_code
+end
@@ -0,0 +1,150 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kernelle-soft/gimme/internal/state"
+)
+
+// dirName is the directory in gimme's state directory that named snapshots
+// are kept in.
+const dirName = "snapshots"
+
+var (
+	ErrNotFound = errors.New("no such snapshot")
+	ErrExists   = errors.New("snapshot already exists")
+)
+
+// Repo is where a repository was when a snapshot was taken.
+type Repo struct {
+	Path   string `json:"path"`
+	Branch string `json:"branch,omitempty"` // empty when detached
+	Head   string `json:"head"`
+}
+
+// Snapshot records the commit each of a set of repositories was at, so it can
+// be checked out again later.
+type Snapshot struct {
+	Created      time.Time       `json:"created"`
+	Repositories map[string]Repo `json:"repositories"` // by identifier
+}
+
+// New returns an empty snapshot taken now.
+func New() *Snapshot {
+	return &Snapshot{Created: time.Now(), Repositories: map[string]Repo{}}
+}
+
+// File returns where the snapshot called name is kept. Names that look like
+// paths, containing a slash or ending in .json, are used as they are, so a
+// snapshot can be written somewhere it can be shared.
+func File(name string) (string, error) {
+	if strings.ContainsRune(name, filepath.Separator) || strings.HasSuffix(name, ".json") {
+		return filepath.Abs(name)
+	}
+	return state.File(filepath.Join(dirName, name+".json"))
+}
+
+// Load reads the snapshot called name.
+func Load(name string) (*Snapshot, error) {
+	file, err := File(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	s := New()
+	if err := state.ReadJSON(file, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes the snapshot as name. An existing snapshot is only replaced
+// with overwrite; otherwise it's ErrExists.
+func (s *Snapshot) Save(name string, overwrite bool) error {
+	file, err := File(name)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(file); err == nil && !overwrite {
+		return ErrExists
+	}
+	return state.WriteJSON(file, s)
+}
+
+// Delete removes the snapshot called name.
+func Delete(name string) error {
+	file, err := File(name)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// List returns the names of the snapshots in the state directory, sorted.
+func List() ([]string, error) {
+	dir, err := state.File(dirName)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// Change is a repository that differs between two snapshots. Before is nil
+// when it was added, and After when it was removed.
+type Change struct {
+	Identifier string
+	Before     *Repo
+	After      *Repo
+}
+
+// Diff returns the repositories whose branch or commit differ between before
+// and after, or that only one of them has, sorted by identifier.
+func Diff(before, after *Snapshot) []Change {
+	changes := []Change{}
+	for id, b := range before.Repositories {
+		a, ok := after.Repositories[id]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Identifier: id, Before: &b})
+		case a.Head != b.Head || a.Branch != b.Branch:
+			changes = append(changes, Change{Identifier: id, Before: &b, After: &a})
+		}
+	}
+	for id, a := range after.Repositories {
+		if _, ok := before.Repositories[id]; !ok {
+			changes = append(changes, Change{Identifier: id, After: &a})
+		}
+	}
+
+	slices.SortFunc(changes, func(x, y Change) int {
+		return strings.Compare(x.Identifier, y.Identifier)
+	})
+	return changes
+}
@@ -0,0 +1,94 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestSaveLoadList(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-snapshot-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("XDG_STATE_HOME", tmpDir)
+
+	s := New()
+	s.Repositories["github.com/acme/api"] = Repo{Path: "/src/api", Branch: "main", Head: "1111111"}
+	if err := s.Save("bug-123", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save("bug-123", false); !errors.Is(err, ErrExists) {
+		t.Errorf("Save() over an existing snapshot = %v, want ErrExists", err)
+	}
+	if err := s.Save("bug-123", true); err != nil {
+		t.Errorf("Save() with overwrite = %v", err)
+	}
+
+	loaded, err := Load("bug-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Repositories, s.Repositories) || !loaded.Created.Equal(s.Created) {
+		t.Errorf("Load() = %+v, want %+v", loaded, s)
+	}
+
+	// Paths are used as they are, outside the state directory
+	shared := filepath.Join(tmpDir, "shared", "lock.json")
+	if err := s.Save(shared, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(shared); err != nil {
+		t.Errorf("Save(%q) didn't write there: %v", shared, err)
+	}
+
+	names, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(names, []string{"bug-123"}) {
+		t.Errorf("List() = %v, want [bug-123]", names)
+	}
+
+	if err := Delete("bug-123"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load("bug-123"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load() after Delete() = %v, want ErrNotFound", err)
+	}
+	if err := Delete("bug-123"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() of a missing snapshot = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := New()
+	before.Repositories = map[string]Repo{
+		"same":    {Branch: "main", Head: "aaa"},
+		"moved":   {Branch: "main", Head: "bbb"},
+		"switch":  {Branch: "main", Head: "ccc"},
+		"removed": {Branch: "main", Head: "ddd"},
+	}
+	after := New()
+	after.Repositories = map[string]Repo{
+		"same":   {Branch: "main", Head: "aaa"},
+		"moved":  {Branch: "main", Head: "eee"},
+		"switch": {Branch: "feature", Head: "ccc"},
+		"added":  {Head: "fff"},
+	}
+
+	changes := Diff(before, after)
+	expected := []Change{
+		{Identifier: "added", After: &Repo{Head: "fff"}},
+		{Identifier: "moved", Before: &Repo{Branch: "main", Head: "bbb"}, After: &Repo{Branch: "main", Head: "eee"}},
+		{Identifier: "removed", Before: &Repo{Branch: "main", Head: "ddd"}},
+		{Identifier: "switch", Before: &Repo{Branch: "main", Head: "ccc"}, After: &Repo{Branch: "feature", Head: "ccc"}},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Diff() = %+v, want %+v", changes, expected)
+	}
+}
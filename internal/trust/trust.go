@@ -0,0 +1,71 @@
+// Package trust remembers which repository config files the user has allowed
+// to change their shell. A file is trusted by a hash of its contents, so once
+// it changes it has to be trusted again.
+package trust
+
+import (
+	"github.com/kernelle-soft/gimme/internal/state"
+)
+
+const fileName = "trusted.json"
+
+// Store is the persisted set of trusted files.
+type Store struct {
+	Files map[string]string `json:"files"` // path to the hash of the contents trusted
+
+	path string
+}
+
+// Load reads the store from gimme's state directory.
+func Load() (*Store, error) {
+	path, err := state.File(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads the store from a specific file.
+func LoadFile(path string) (*Store, error) {
+	s := &Store{}
+	if err := state.ReadJSON(path, s); err != nil {
+		return nil, err
+	}
+	if s.Files == nil {
+		s.Files = map[string]string{}
+	}
+	s.path = path
+	return s, nil
+}
+
+// Save writes the store back to the file it was loaded from.
+func (s *Store) Save() error {
+	return state.WriteJSON(s.path, s)
+}
+
+// IsTrusted reports whether the file at path was trusted with contents that
+// hash to hash.
+func (s *Store) IsTrusted(path, hash string) bool {
+	return hash != "" && s.Files[path] == hash
+}
+
+// Trust trusts the file at path for as long as its contents hash to hash.
+func (s *Store) Trust(path, hash string) {
+	s.Files[path] = hash
+}
+
+// Revoke stops trusting the file at path, returning false if it wasn't.
+func (s *Store) Revoke(path string) bool {
+	if _, ok := s.Files[path]; !ok {
+		return false
+	}
+	delete(s.Files, path)
+	return true
+}
+
+// IsTrusted loads the store and reports whether the file at path is trusted
+// with contents that hash to hash. A store that can't be read trusts nothing.
+func IsTrusted(path, hash string) bool {
+	s, err := Load()
+	return err == nil && s.IsTrusted(path, hash)
+}
@@ -0,0 +1,50 @@
+package trust
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gimme-trust-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	file := filepath.Join(tmpDir, "trusted.json")
+	s, err := LoadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.IsTrusted("/src/api/.gimme.yaml", "abc") {
+		t.Error("empty store trusts a file")
+	}
+
+	s.Trust("/src/api/.gimme.yaml", "abc")
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = LoadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsTrusted("/src/api/.gimme.yaml", "abc") {
+		t.Error("trusted file not trusted after reloading")
+	}
+	if s.IsTrusted("/src/api/.gimme.yaml", "def") {
+		t.Error("file trusted after its contents changed")
+	}
+	if s.IsTrusted("/src/api/.gimme.yaml", "") {
+		t.Error("missing file trusted")
+	}
+
+	if !s.Revoke("/src/api/.gimme.yaml") || s.IsTrusted("/src/api/.gimme.yaml", "abc") {
+		t.Error("Revoke() didn't stop trusting the file")
+	}
+	if s.Revoke("/src/api/.gimme.yaml") {
+		t.Error("Revoke() of an untrusted file returned true")
+	}
+}
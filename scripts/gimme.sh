@@ -1,14 +1,5 @@
 #!/usr/bin/env bash
 
-function gimme() {
-    local output
-    output="$(command gimme "$@")"
-    local exit_code="$?"
-    
-    if [[ "$output" == cd://* ]]; then
-        cd "${output#cd://}" || return 1
-    else
-        [[ -n "$output" ]] && echo "$output"
-        return $exit_code
-    fi
-}
\ No newline at end of file
+# Kept for existing setups that source this file. New setups can put the
+# same line in ~/.bashrc directly; see 'gimme init --help'.
+eval "$(command gimme init bash)"